@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ByteSize is a size in bytes that can be parsed from human readable values like "64MiB".
+type ByteSize uint64
+
+// Byte size units.
+const (
+	Byte ByteSize = 1
+
+	KB ByteSize = 1000 * Byte
+	MB ByteSize = 1000 * KB
+	GB ByteSize = 1000 * MB
+	TB ByteSize = 1000 * GB
+	PB ByteSize = 1000 * TB
+
+	KiB ByteSize = 1024 * Byte
+	MiB ByteSize = 1024 * KiB
+	GiB ByteSize = 1024 * MiB
+	TiB ByteSize = 1024 * GiB
+	PiB ByteSize = 1024 * TiB
+)
+
+type byteSizeUnit struct {
+	name string
+	size ByteSize
+}
+
+// byteSizeUnits contains the units ordered by descending size.
+var byteSizeUnits = []byteSizeUnit{
+	{"PiB", PiB}, {"PB", PB}, {"TiB", TiB}, {"TB", TB}, {"GiB", GiB},
+	{"GB", GB}, {"MiB", MiB}, {"MB", MB}, {"KiB", KiB}, {"KB", KB},
+}
+
+// byteSizeSuffixes maps all accepted unit suffixes in upper case to their size.
+var byteSizeSuffixes = map[string]ByteSize{
+	"":  Byte,
+	"B": Byte,
+	"K": KB, "KB": KB, "M": MB, "MB": MB, "G": GB, "GB": GB, "T": TB, "TB": TB, "P": PB, "PB": PB,
+	"KI": KiB, "KIB": KiB, "MI": MiB, "MIB": MiB, "GI": GiB, "GIB": GiB,
+	"TI": TiB, "TIB": TiB, "PI": PiB, "PIB": PiB,
+}
+
+// ParseByteSize parses a human readable byte size like "512", "64MiB", "1.5 GB" or "2Gi".
+// Units are case-insensitive, SI units like MB are based on 1000 and binary units like
+// MiB or Mi are based on 1024.
+func ParseByteSize(s string) (ByteSize, error) {
+	s = strings.TrimSpace(s)
+	number := strings.TrimRight(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ ")
+	suffix := strings.ToUpper(strings.TrimSpace(s[len(number):]))
+
+	unit, ok := byteSizeSuffixes[suffix]
+	if !ok || number == "" {
+		return 0, fmt.Errorf("invalid byte size '%s'", s)
+	}
+
+	if value, err := strconv.ParseUint(number, 10, 64); err == nil {
+		if value > math.MaxUint64/uint64(unit) {
+			return 0, fmt.Errorf("byte size '%s' is out of range", s)
+		}
+		return ByteSize(value) * unit, nil
+	}
+
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid byte size '%s'", s)
+	}
+	size := value * float64(unit)
+	if size >= math.MaxUint64 {
+		return 0, fmt.Errorf("byte size '%s' is out of range", s)
+	}
+	return ByteSize(size), nil
+}
+
+// String returns the size using the largest unit that represents it without a fraction.
+func (b ByteSize) String() string {
+	for _, unit := range byteSizeUnits {
+		if b >= unit.size && b%unit.size == 0 {
+			return strconv.FormatUint(uint64(b/unit.size), 10) + unit.name
+		}
+	}
+	return strconv.FormatUint(uint64(b), 10) + "B"
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := map[string]ByteSize{
+		"512":     512,
+		"512B":    512,
+		"64MiB":   64 * MiB,
+		"64mib":   64 * MiB,
+		"2Gi":     2 * GiB,
+		"1.5 GB":  1500 * MB,
+		"1.5KiB":  1536,
+		"10k":     10 * KB,
+		" 3 TB ":  3 * TB,
+		"0.5 PiB": PiB / 2,
+	}
+
+	for s, expected := range tests {
+		size, err := ParseByteSize(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, expected, size, s)
+	}
+
+	for _, s := range []string{"", "MiB", "-1", "1XB", "1.2.3MB", "20000000PiB"} {
+		_, err := ParseByteSize(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestByteSizeString(t *testing.T) {
+	assert.Equal(t, "0B", ByteSize(0).String())
+	assert.Equal(t, "123B", ByteSize(123).String())
+	assert.Equal(t, "64MiB", (64 * MiB).String())
+	assert.Equal(t, "1500KB", (1500 * KB).String())
+	assert.Equal(t, "2GB", (2 * GB).String())
+}
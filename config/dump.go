@@ -0,0 +1,143 @@
+package config
+
+import (
+	"encoding"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/cornelk/gotokit/log"
+)
+
+// maskedValue replaces the value of secrets in a config dump.
+const maskedValue = "******"
+
+// Value is a single configuration value of a config dump.
+type Value struct {
+	Key    string // environment variable name without any of the Options prefixes
+	Value  string // formatted value, masked for secrets
+	Secret bool   // whether the value is a secret
+}
+
+// Values contains configuration values in the order of the struct fields.
+type Values []Value
+
+// Dump returns all configuration values of the given config struct, using the same
+// env and envPrefix tags that Read uses. Values of fields that are tagged with
+// `secret:"true"` or have a name that indicates a secret like PASSWORD or TOKEN
+// are masked. Tagging a field with `secret:"false"` disables the masking.
+func Dump(config any) (Values, error) {
+	fields, err := parseFields(config)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(Values, 0, len(fields))
+	for _, f := range fields {
+		value := formatValue(f.value)
+		if f.secret && value != "" {
+			value = maskedValue
+		}
+
+		values = append(values, Value{
+			Key:    f.key,
+			Value:  value,
+			Secret: f.secret,
+		})
+	}
+	return values, nil
+}
+
+// Map returns the values as map of environment variable name to value.
+func (v Values) Map() map[string]string {
+	m := make(map[string]string, len(v))
+	for _, value := range v {
+		m[value.Key] = value.Value
+	}
+	return m
+}
+
+// Field returns the values as log field group with the given key.
+func (v Values) Field(key string) log.Field {
+	attrs := make([]slog.Attr, 0, len(v))
+	for _, value := range v {
+		attrs = append(attrs, log.String(value.Key, value.Value))
+	}
+	return slog.Attr{
+		Key:   key,
+		Value: slog.GroupValue(attrs...),
+	}
+}
+
+// formatValue returns the string representation of a config value, preferring
+// the String and MarshalText methods of the type if implemented.
+func formatValue(v reflect.Value) string {
+	if !v.IsValid() || (v.Kind() == reflect.Pointer && v.IsNil()) {
+		return ""
+	}
+
+	if s, ok := formatInterface(v); ok {
+		return s
+	}
+	if v.CanAddr() {
+		if s, ok := formatInterface(v.Addr()); ok {
+			return s
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		return formatValue(v.Elem())
+
+	case reflect.Slice, reflect.Array:
+		elements := make([]string, 0, v.Len())
+		for i := range v.Len() {
+			elements = append(elements, formatValue(v.Index(i)))
+		}
+		return strings.Join(elements, ",")
+
+	case reflect.Map:
+		// maps with empty struct values are sets, only the keys are of interest
+		keysOnly := v.Type().Elem().Size() == 0
+		elements := make([]string, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			element := formatValue(iter.Key())
+			if !keysOnly {
+				element += ":" + formatValue(iter.Value())
+			}
+			elements = append(elements, element)
+		}
+		slices.Sort(elements)
+		return strings.Join(elements, ",")
+
+	default:
+		if !v.CanInterface() {
+			return ""
+		}
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+func formatInterface(v reflect.Value) (string, bool) {
+	if !v.CanInterface() {
+		return "", false
+	}
+
+	switch val := v.Interface().(type) {
+	case fmt.Stringer:
+		return val.String(), true
+
+	case encoding.TextMarshaler:
+		text, err := val.MarshalText()
+		if err != nil {
+			return "", false
+		}
+		return string(text), true
+
+	default:
+		return "", false
+	}
+}
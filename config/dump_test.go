@@ -0,0 +1,62 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDump(t *testing.T) {
+	type Database struct {
+		Host     string `env:"HOST"`
+		Password string `env:"PASSWORD"`
+	}
+
+	type myConfig struct {
+		Database Database      `envPrefix:"DATABASE_"`
+		Timeout  time.Duration `env:"TIMEOUT"`
+		APIKey   string        `env:"API_KEY"`
+		Seed     string        `env:"SEED" secret:"true"`
+		Token    string        `env:"TOKEN" secret:"false"`
+		Hosts    []string      `env:"HOSTS"`
+		Ignored  string        `env:"-"`
+		internal string
+	}
+
+	cfg := myConfig{
+		Database: Database{
+			Host:     "localhost",
+			Password: "hunter2",
+		},
+		Timeout:  5 * time.Second,
+		APIKey:   "key",
+		Token:    "public",
+		Hosts:    []string{"a", "b"},
+		Ignored:  "ignored",
+		internal: "internal",
+	}
+
+	values, err := Dump(&cfg)
+	require.NoError(t, err)
+
+	expected := Values{
+		{Key: "DATABASE_HOST", Value: "localhost"},
+		{Key: "DATABASE_PASSWORD", Value: maskedValue, Secret: true},
+		{Key: "TIMEOUT", Value: "5s"},
+		{Key: "API_KEY", Value: maskedValue, Secret: true},
+		{Key: "SEED", Value: "", Secret: true},
+		{Key: "TOKEN", Value: "public"},
+		{Key: "HOSTS", Value: "a,b"},
+	}
+	assert.Equal(t, expected, values)
+	assert.Equal(t, "localhost", values.Map()["DATABASE_HOST"])
+
+	field := values.Field("config")
+	assert.Equal(t, "config", field.Key)
+	assert.Len(t, field.Value.Group(), len(expected))
+
+	_, err = Dump("invalid")
+	require.Error(t, err)
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/cornelk/gotokit/env"
+)
+
+// tagEnvDefaultPrefix is the prefix of tags that define a default value for a specific
+// environment, for example `envDefault.prod:"50"`.
+const tagEnvDefaultPrefix = tagEnvDefault + "."
+
+// EnvironmentDefault is a default value that only applies in a specific environment.
+type EnvironmentDefault struct {
+	Environment env.Environment
+	Value       string
+}
+
+// applyEnvironmentDefaults validates the environment names of all environment specific
+// default tags and applies the defaults for the given environment. The default is set
+// in the values if no value is set for the field. An empty environment only validates
+// the tags.
+func applyEnvironmentDefaults(values map[string]string, fields []field, current env.Environment) error {
+	if current != "" {
+		// resolve aliases and case to compare with the environments of the tags
+		var err error
+		current, err = env.Parse(string(current))
+		if err != nil {
+			return fmt.Errorf("validating environment: %w", err)
+		}
+	}
+
+	for i := range fields {
+		f := &fields[i]
+
+		defaults, err := environmentDefaults(*f)
+		if err != nil {
+			return err
+		}
+
+		for _, def := range defaults {
+			if current == "" || def.Environment != current {
+				continue
+			}
+
+			f.defaultValue = def.Value
+			f.hasDefault = true
+			if values[f.key] == "" {
+				values[f.key] = f.defaultValue
+			}
+		}
+	}
+
+	return nil
+}
+
+// environmentDefaults returns the environment specific defaults of the field in the
+// order of the tags. An error is returned for tags with unknown environment names.
+func environmentDefaults(f field) ([]EnvironmentDefault, error) {
+	var defaults []EnvironmentDefault
+
+	for _, key := range tagKeys(f.tag) {
+		name, ok := strings.CutPrefix(key, tagEnvDefaultPrefix)
+		if !ok {
+			continue
+		}
+
+		environment, err := env.Parse(name)
+		if err != nil {
+			return nil, fmt.Errorf("tag %s of field %s: %w", key, f.path, err)
+		}
+		defaults = append(defaults, EnvironmentDefault{
+			Environment: environment,
+			Value:       f.tag.Get(key),
+		})
+	}
+
+	return defaults, nil
+}
+
+// formatEnvironmentDefaults returns the environment specific defaults in the format
+// env: value, separated by commas.
+func formatEnvironmentDefaults(defaults []EnvironmentDefault) string {
+	parts := make([]string, 0, len(defaults))
+	for _, def := range defaults {
+		parts = append(parts, def.Environment.String()+": "+def.Value)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// tagKeys returns the keys of all tags in the struct tag, following the conventional
+// format that is parsed by reflect.StructTag.Lookup.
+func tagKeys(tag reflect.StructTag) []string {
+	var keys []string
+
+	for tag != "" {
+		// skip leading space
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+
+		// scan to colon, a space, a quote or a control character is a syntax error
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		key := string(tag[:i])
+		tag = tag[i+1:]
+
+		// scan quoted string to find the value
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		if _, err := strconv.Unquote(string(tag[:i+1])); err != nil {
+			break
+		}
+		tag = tag[i+1:]
+
+		keys = append(keys, key)
+	}
+
+	return keys
+}
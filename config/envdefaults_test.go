@@ -0,0 +1,65 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cornelk/gotokit/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadEnvironmentDefaults(t *testing.T) {
+	type myConfig struct {
+		PoolSize   int  `env:"POOL_SIZE" envDefault:"5" envDefault.prod:"50" envDefault.local:"1"`
+		JSONOutput bool `env:"JSON_OUTPUT" envDefault.production:"true"`
+	}
+
+	tests := []struct {
+		environment env.Environment
+		poolSize    int
+		jsonOutput  bool
+	}{
+		{environment: "", poolSize: 5},
+		{environment: env.Development, poolSize: 5},
+		{environment: env.Local, poolSize: 1},
+		{environment: env.Production, poolSize: 50, jsonOutput: true},
+		{environment: "production", poolSize: 50, jsonOutput: true},
+		{environment: "PROD", poolSize: 50, jsonOutput: true},
+	}
+
+	for _, tt := range tests {
+		var provenance Provenance
+		var cfg myConfig
+		require.NoError(t, Read(&cfg, Options{Environment: tt.environment, Provenance: &provenance}))
+		assert.Equal(t, tt.poolSize, cfg.PoolSize, tt.environment)
+		assert.Equal(t, tt.jsonOutput, cfg.JSONOutput, tt.environment)
+		assert.Equal(t, OriginDefault, provenance.Origins[0].Kind)
+	}
+
+	// set values take precedence over environment defaults
+	t.Setenv("POOL_SIZE", "10")
+	var cfg myConfig
+	require.NoError(t, Read(&cfg, Options{Environment: env.Production}))
+	assert.Equal(t, 10, cfg.PoolSize)
+
+	require.Error(t, Read(&cfg, Options{Environment: "invalid"}))
+}
+
+func TestReadEnvironmentDefaultsInvalidTag(t *testing.T) {
+	type myConfig struct {
+		PoolSize int `env:"POOL_SIZE" envDefault.prd:"50"`
+	}
+
+	var cfg myConfig
+	err := Read(&cfg, Options{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "envDefault.prd")
+}
+
+func TestTagKeys(t *testing.T) {
+	tag := reflect.StructTag(`env:"A,required" envDefault:"x y" envDefault.prod:"\"q\"" desc:"a:b"`)
+	assert.Equal(t, []string{"env", "envDefault", "envDefault.prod", "desc"}, tagKeys(tag))
+	assert.Empty(t, tagKeys(""))
+	assert.Equal(t, []string{"env"}, tagKeys(`env:"A" invalid`))
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Struct tags that are used by the config package in addition to the env parser tags.
+const (
+	tagEnv        = "env"
+	tagEnvDefault = "envDefault"
+	tagEnvPrefix  = "envPrefix"
+	tagDesc       = "desc"
+	tagSecret     = "secret"
+)
+
+// secretKeyParts contains parts of environment variable names that mark a value
+// as secret if no explicit secret tag is set for a field.
+var secretKeyParts = []string{
+	"API_KEY",
+	"APIKEY",
+	"CREDENTIAL",
+	"PASSPHRASE",
+	"PASSWD",
+	"PASSWORD",
+	"PRIVATE_KEY",
+	"SECRET",
+	"TOKEN",
+}
+
+// field describes a single configuration value of a config struct.
+type field struct {
+	path         string            // path of the struct field, for example Database.Host
+	key          string            // environment variable name without any of the Options prefixes
+	tag          reflect.StructTag // all tags of the struct field
+	typ          reflect.Type      // type of the struct field
+	defaultValue string
+	hasDefault   bool
+	required     bool
+	secret       bool
+	value        reflect.Value
+}
+
+// parseFields returns all configuration values of the given config struct. Nested structs
+// are walked the same way that the env parser does it, honoring the envPrefix tag.
+func parseFields(config any) ([]field, error) {
+	v := reflect.ValueOf(config)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v = reflect.New(v.Type().Elem())
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("config must be a struct or a pointer to a struct, got %T", config)
+	}
+
+	var fields []field
+	walkStruct(v, "", "", &fields)
+	return fields, nil
+}
+
+func walkStruct(v reflect.Value, path, prefix string, fields *[]field) {
+	t := v.Type()
+
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		fieldPath := sf.Name
+		if path != "" {
+			fieldPath = path + "." + sf.Name
+		}
+
+		key, options := parseEnvTag(sf.Tag.Get(tagEnv))
+		if key == "-" || slices.Contains(options, "-") {
+			continue
+		}
+
+		if key == "" {
+			if nested, ok := nestedStruct(v.Field(i)); ok {
+				walkStruct(nested, fieldPath, prefix+sf.Tag.Get(tagEnvPrefix), fields)
+			}
+			continue
+		}
+
+		defaultValue, hasDefault := sf.Tag.Lookup(tagEnvDefault)
+		f := field{
+			path:         fieldPath,
+			key:          prefix + key,
+			tag:          sf.Tag,
+			typ:          sf.Type,
+			defaultValue: defaultValue,
+			hasDefault:   hasDefault,
+			required:     slices.Contains(options, "required"),
+			value:        v.Field(i),
+		}
+		f.secret = isSecret(sf.Tag, f.key)
+		*fields = append(*fields, f)
+	}
+}
+
+// nestedStruct returns the struct value of a struct or pointer to struct field.
+// Nil pointers are replaced by a zero value to be able to walk its fields.
+func nestedStruct(v reflect.Value) (reflect.Value, bool) {
+	if v.Kind() == reflect.Pointer && v.Type().Elem().Kind() == reflect.Struct {
+		if v.IsNil() {
+			return reflect.New(v.Type().Elem()).Elem(), true
+		}
+		return v.Elem(), true
+	}
+	if v.Kind() == reflect.Struct {
+		return v, true
+	}
+	return reflect.Value{}, false
+}
+
+// parseEnvTag splits the env tag into the key and its options.
+func parseEnvTag(tag string) (string, []string) {
+	parts := strings.Split(tag, ",")
+	return parts[0], parts[1:]
+}
+
+// isSecret returns whether the value of a field is secret. An explicitly set
+// secret tag takes precedence over the detection based on the key name,
+// an invalid tag value is treated as secret.
+func isSecret(tag reflect.StructTag, key string) bool {
+	if value, ok := tag.Lookup(tagSecret); ok {
+		secret, err := strconv.ParseBool(value)
+		return err != nil || secret
+	}
+
+	key = strings.ToUpper(key)
+	for _, part := range secretKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
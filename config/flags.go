@@ -0,0 +1,126 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// flagValue is a flag that stores the raw string value, the value is parsed
+// together with all other config values.
+type flagValue struct {
+	value  string
+	isBool bool
+}
+
+// String returns the value of the flag.
+func (v *flagValue) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.value
+}
+
+// Set sets the value of the flag.
+func (v *flagValue) Set(s string) error {
+	v.value = s
+	return nil
+}
+
+// IsBoolFlag returns whether the flag can be used without a value.
+func (v *flagValue) IsBoolFlag() bool {
+	return v.isBool
+}
+
+// flagName returns the command line flag name for an environment variable name,
+// for example DATABASE_HOST is turned into database-host.
+func flagName(key string) string {
+	return strings.ReplaceAll(strings.ToLower(key), "_", "-")
+}
+
+// flagUsage returns the usage of the flag of a field, containing the description,
+// the environment variable names and the environment specific defaults.
+func flagUsage(f field, prefixes []string) (string, error) {
+	names := make([]string, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		names = append(names, prefix+f.key)
+	}
+	usage := f.tag.Get(tagDesc)
+	if usage != "" {
+		usage += " "
+	}
+	usage += "(env " + strings.Join(names, ", ") + ")"
+	if f.secret {
+		return usage, nil
+	}
+
+	defaults, err := environmentDefaults(f)
+	if err != nil {
+		return "", err
+	}
+	if len(defaults) > 0 {
+		usage += " (environment defaults " + formatEnvironmentDefaults(defaults) + ")"
+	}
+	return usage, nil
+}
+
+// applyFlags defines a flag for every config field in the flag set of the options,
+// parses the command line arguments and overrides the environment values for all
+// prefixes with the values of the flags that were set. It returns the names of the
+// flags that were set, indexed by the environment variable names.
+func applyFlags(environment map[string]string, fields []field, prefixes []string,
+	opts Options) (map[string]string, error) {
+
+	fs := opts.FlagSet
+	for _, f := range fields {
+		name := flagName(f.key)
+		if fs.Lookup(name) != nil {
+			continue // flag was defined by a previous read
+		}
+
+		usage, err := flagUsage(f, prefixes)
+		if err != nil {
+			return nil, err
+		}
+
+		value := &flagValue{
+			isBool: f.typ.Kind() == reflect.Bool,
+		}
+		fs.Var(value, name, usage)
+		if !f.secret {
+			fs.Lookup(name).DefValue = f.defaultValue
+		}
+	}
+
+	if !fs.Parsed() {
+		args := opts.Args
+		if args == nil {
+			args = os.Args[1:]
+		}
+		if err := fs.Parse(args); err != nil {
+			return nil, fmt.Errorf("parsing command line flags: %w", err)
+		}
+	}
+
+	set := map[string]struct{}{}
+	fs.Visit(func(fl *flag.Flag) {
+		set[fl.Name] = struct{}{}
+	})
+
+	flags := map[string]string{}
+	for _, f := range fields {
+		name := flagName(f.key)
+		if _, ok := set[name]; !ok {
+			continue
+		}
+
+		value := fs.Lookup(name).Value.String()
+		for _, prefix := range prefixes {
+			environment[prefix+f.key] = value
+			flags[prefix+f.key] = name
+		}
+	}
+	return flags, nil
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cornelk/gotokit/envfile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type flagConfig struct {
+	Database struct {
+		Host string `env:"HOST" envDefault:"localhost" desc:"Database host name"`
+		Port int    `env:"PORT" envDefault:"5432" envDefault.local:"5433"`
+	} `envPrefix:"DATABASE_"`
+	Debug bool `env:"DEBUG"`
+}
+
+func TestReadFlags(t *testing.T) {
+	t.Setenv("DATABASE_HOST", "envhost")
+	t.Setenv("TESTAPP_DATABASE_PORT", "1234")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	opts := Options{
+		Prefixes: []string{"", "testapp"},
+		FlagSet:  fs,
+		Args:     []string{"-database-host", "flaghost", "-debug"},
+	}
+
+	var cfg flagConfig
+	require.NoError(t, Read(&cfg, opts))
+	assert.Equal(t, "flaghost", cfg.Database.Host)
+	assert.Equal(t, 1234, cfg.Database.Port)
+	assert.True(t, cfg.Debug)
+
+	// reading again reuses the defined and parsed flags
+	cfg = flagConfig{}
+	require.NoError(t, Read(&cfg, opts))
+	assert.Equal(t, "flaghost", cfg.Database.Host)
+}
+
+func TestReadFlagsHelp(t *testing.T) {
+	var buf bytes.Buffer
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(&buf)
+
+	opts := Options{
+		FlagSet: fs,
+		Args:    []string{"-help"},
+	}
+
+	var cfg flagConfig
+	require.ErrorIs(t, Read(&cfg, opts), flag.ErrHelp)
+
+	help := buf.String()
+	assert.Contains(t, help, "-database-host value")
+	assert.Contains(t, help, "Database host name (env DATABASE_HOST) (default localhost)")
+	assert.Contains(t, help, "(env DATABASE_PORT) (environment defaults local: 5433) (default 5432)")
+	assert.Contains(t, help, "-debug")
+}
+
+func TestReadFlagsPrecedence(t *testing.T) {
+	t.Setenv("DATABASE_HOST", "envhost")
+	t.Setenv("DATABASE_PORT", "")
+	t.Setenv("DEBUG", "")
+	require.NoError(t, os.Unsetenv("DATABASE_PORT"))
+	require.NoError(t, os.Unsetenv("DEBUG"))
+
+	directory := t.TempDir()
+	data := "DATABASE_HOST=filehost\nDATABASE_PORT=1234\nDEBUG=false\n"
+	require.NoError(t, os.WriteFile(filepath.Join(directory, ".env"), []byte(data), 0o644))
+
+	previous, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(directory))
+	t.Cleanup(func() {
+		_ = os.Chdir(previous)
+	})
+
+	// the environment takes precedence over .env files if they are loaded without override
+	_, err = envfile.LoadEnvironment("", envfile.CascadeOptions{NoOverride: true})
+	require.NoError(t, err)
+
+	opts := Options{
+		FlagSet: flag.NewFlagSet("test", flag.ContinueOnError),
+		Args:    []string{"-debug"},
+	}
+	var cfg flagConfig
+	require.NoError(t, Read(&cfg, opts))
+	assert.Equal(t, "envhost", cfg.Database.Host)
+	assert.Equal(t, 1234, cfg.Database.Port)
+	assert.True(t, cfg.Debug)
+}
@@ -1,16 +1,62 @@
 package config
 
 import (
+	"flag"
 	"reflect"
 
-	"github.com/caarlos0/env/v11"
+	envparser "github.com/caarlos0/env/v11"
+	"github.com/cornelk/gotokit/env"
 )
 
 // ParserFunc defines the signature of a function that can be used within `CustomParsers`.
-type ParserFunc = env.ParserFunc
+type ParserFunc = envparser.ParserFunc
 
 // Options for the config reader.
 type Options struct {
 	Prefixes []string                    // Prefixes define a prefix for each key.
 	FuncMap  map[reflect.Type]ParserFunc // Custom parse functions for different types.
+
+	// Environment enables environment specific default values, that are defined by tags
+	// like `envDefault:"5" envDefault.prod:"50" envDefault.local:"1"`. The environment
+	// names of the tags are validated to be known environments of package env.
+	Environment env.Environment
+
+	// Variables replaces the process environment as source of the environment variables
+	// if set, for example with the variables returned by envfile.Read. This allows reading
+	// configs in parallel tests without modifying the process environment.
+	Variables map[string]string
+
+	// Sources provide config values from key/value stores. Their values override values
+	// loaded from env files, but not variables set directly in the environment. Values of
+	// later sources override the ones of earlier sources.
+	Sources []Source
+
+	// SecretFiles enables reading values from files, as used for Docker and Kubernetes
+	// secrets. A file can be referenced by a variable with a _FILE suffix, for example
+	// DATABASE_PASSWORD_FILE. Values of secret fields can also use the file:// scheme.
+	SecretFiles bool
+	// MaxSecretFileSize limits the size of secret files, defaults to DefaultMaxSecretFileSize.
+	MaxSecretFileSize int64
+	// OnSecretFile is called for every value that was read from a secret file.
+	OnSecretFile func(SecretFile)
+
+	// FlagSet enables command line flags for all config values. The flag names are
+	// derived from the environment variable names, for example DATABASE_HOST can be
+	// set using -database-host. Flags take precedence over environment variables.
+	// The description of a flag can be set using the desc tag of the field.
+	// The full precedence of flags > environment > .env files > defaults requires
+	// loading the .env files without overriding variables of the environment, using
+	// envfile.LoadEnvironment with CascadeOptions{NoOverride: true}. Load and LoadFiles
+	// of the envfile package override variables of the environment.
+	FlagSet *flag.FlagSet
+	// Args are the command line arguments to parse, defaults to os.Args[1:].
+	Args []string
+
+	// Strict enables the detection of unknown environment variables. Read returns an
+	// UnknownVariablesError if a variable with one of the non-empty prefixes does not
+	// map to any config field.
+	Strict bool
+
+	// Provenance gets filled with the origins of all config values if set.
+	Provenance *Provenance
 }
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/cornelk/gotokit/log"
+	"github.com/cornelk/gotokit/set"
+)
+
+var textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+// defaultParsers returns the parsers that Read uses for project and common types in addition
+// to the parsers of the env package, which already supports url.URL, time.Location and
+// time.Duration as well as all types implementing encoding.TextUnmarshaler like netip.Addr,
+// netip.Prefix, regexp.Regexp and env.Environment. Parsers set in the Options override the
+// default parsers.
+func defaultParsers() map[reflect.Type]ParserFunc {
+	return map[reflect.Type]ParserFunc{
+		reflect.TypeFor[ByteSize]():        parseByteSize,
+		reflect.TypeFor[log.Level]():       parseLevel,
+		reflect.TypeFor[set.Set[string]](): parseStringSet,
+	}
+}
+
+func parseByteSize(v string) (any, error) {
+	return ParseByteSize(v)
+}
+
+func parseLevel(v string) (any, error) {
+	return log.ParseLevel(v)
+}
+
+// parseStringSet parses a comma separated list of strings into a set.
+func parseStringSet(v string) (any, error) {
+	s := set.New[string]()
+	for _, element := range strings.Split(v, ",") {
+		if element = strings.TrimSpace(element); element != "" {
+			s.Add(element)
+		}
+	}
+	return s, nil
+}
+
+// normalizeTextValues parses the values of fields with types that implement
+// encoding.TextUnmarshaler and have a registered parser, and replaces the values with the
+// text representation of the parsed value. The env package prefers the UnmarshalText
+// function of a type over registered parsers, this allows parsers to extend or override
+// the accepted formats, for example to support the additional log levels TRACE and FATAL.
+func normalizeTextValues(values map[string]string, fields []field, parsers map[reflect.Type]ParserFunc) error {
+	for _, f := range fields {
+		typ := f.typ
+		if typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+
+		parser, ok := parsers[typ]
+		if !ok || !reflect.PointerTo(typ).Implements(textUnmarshalerType) {
+			continue
+		}
+
+		value := values[f.key]
+		if value == "" {
+			if !f.hasDefault || f.defaultValue == "" {
+				continue
+			}
+			value = f.defaultValue
+		}
+
+		parsed, err := parser(value)
+		if err != nil {
+			return fmt.Errorf("parsing value of %s: %w", f.key, err)
+		}
+		marshaler, ok := parsed.(encoding.TextMarshaler)
+		if !ok {
+			continue
+		}
+		text, err := marshaler.MarshalText()
+		if err != nil {
+			return fmt.Errorf("marshaling value of %s: %w", f.key, err)
+		}
+		values[f.key] = string(text)
+	}
+
+	return nil
+}
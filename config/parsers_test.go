@@ -0,0 +1,83 @@
+package config
+
+import (
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/cornelk/gotokit/env"
+	"github.com/cornelk/gotokit/log"
+	"github.com/cornelk/gotokit/set"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadDefaultParsers(t *testing.T) {
+	type myConfig struct {
+		Level        log.Level       `env:"LEVEL"`
+		DefaultLevel log.Level       `env:"DEFAULT_LEVEL" envDefault:"fatal"`
+		Environment  env.Environment `env:"ENVIRONMENT"`
+		Hosts        set.Set[string] `env:"HOSTS"`
+		URL          *url.URL        `env:"URL"`
+		Addr         netip.Addr      `env:"ADDR"`
+		Prefix       netip.Prefix    `env:"PREFIX"`
+		Location     *time.Location  `env:"LOCATION"`
+		Size         ByteSize        `env:"SIZE"`
+		Regexp       *regexp.Regexp  `env:"REGEXP"`
+	}
+
+	t.Setenv("LEVEL", "TRACE")
+	t.Setenv("ENVIRONMENT", "production")
+	t.Setenv("HOSTS", "a, b,,c")
+	t.Setenv("URL", "https://example.com/path")
+	t.Setenv("ADDR", "10.0.0.1")
+	t.Setenv("PREFIX", "10.0.0.0/8")
+	t.Setenv("LOCATION", "Europe/Berlin")
+	t.Setenv("SIZE", "64MiB")
+	t.Setenv("REGEXP", "^a+$")
+
+	var cfg myConfig
+	require.NoError(t, Read(&cfg, Options{}))
+
+	assert.Equal(t, log.TraceLevel, cfg.Level)
+	assert.Equal(t, log.FatalLevel, cfg.DefaultLevel)
+	assert.Equal(t, env.Production, cfg.Environment)
+	assert.Equal(t, set.NewFromSlice([]string{"a", "b", "c"}), cfg.Hosts)
+	assert.Equal(t, "example.com", cfg.URL.Host)
+	assert.Equal(t, netip.MustParseAddr("10.0.0.1"), cfg.Addr)
+	assert.Equal(t, netip.MustParsePrefix("10.0.0.0/8"), cfg.Prefix)
+	assert.Equal(t, "Europe/Berlin", cfg.Location.String())
+	assert.Equal(t, 64*MiB, cfg.Size)
+	assert.True(t, cfg.Regexp.MatchString("aaa"))
+
+	t.Setenv("ENVIRONMENT", "invalid")
+	require.Error(t, Read(&cfg, Options{}))
+}
+
+func TestReadParserOverride(t *testing.T) {
+	type myConfig struct {
+		Level log.Level `env:"LEVEL"`
+	}
+
+	t.Setenv("LEVEL", "verbose")
+
+	opts := Options{
+		FuncMap: map[reflect.Type]ParserFunc{
+			reflect.TypeFor[log.Level](): func(v string) (any, error) {
+				if v == "verbose" {
+					return log.TraceLevel, nil
+				}
+				return log.ParseLevel(v)
+			},
+		},
+	}
+
+	var cfg myConfig
+	require.NoError(t, Read(&cfg, opts))
+	assert.Equal(t, log.TraceLevel, cfg.Level)
+
+	require.Error(t, Read(&cfg, Options{}))
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/cornelk/gotokit/log"
+)
+
+// OriginKind describes the kind of source that supplied a config value.
+type OriginKind string
+
+// Available origin kinds.
+const (
+	OriginUnset      OriginKind = "unset"
+	OriginDefault    OriginKind = "default"
+	OriginEnv        OriginKind = "env"
+	OriginEnvFile    OriginKind = "env file"
+	OriginSecretFile OriginKind = "secret file"
+	OriginFlag       OriginKind = "flag"
+	OriginSource     OriginKind = "source"
+)
+
+// Origin describes where the value of a config field came from.
+type Origin struct {
+	Field    string     // path of the struct field, for example Database.Host
+	Kind     OriginKind // kind of source that supplied the value
+	Variable string     // environment variable that supplied the value, including the prefix
+	Prefix   string     // prefix of the environment variable
+	File     string     // env file or secret file that supplied the value
+	Flag     string     // command line flag that supplied the value
+	Source   string     // name of the config source that supplied the value
+}
+
+// String returns a human readable description of the origin.
+func (o Origin) String() string {
+	switch o.Kind {
+	case OriginEnv:
+		return fmt.Sprintf("%s %s", o.Kind, o.Variable)
+	case OriginEnvFile, OriginSecretFile:
+		return fmt.Sprintf("%s %s (%s)", o.Kind, o.File, o.Variable)
+	case OriginFlag:
+		return fmt.Sprintf("%s -%s", o.Kind, o.Flag)
+	case OriginSource:
+		return fmt.Sprintf("%s %s (%s)", o.Kind, o.Source, o.Variable)
+	default:
+		return string(o.Kind)
+	}
+}
+
+// Provenance contains the origins of all config values in the order of the struct fields.
+// Pass a pointer to a Provenance in the Options to have Read fill it.
+type Provenance struct {
+	Origins []Origin
+}
+
+// Lookup returns the origin of the config field with the given path.
+func (p *Provenance) Lookup(field string) (Origin, bool) {
+	for _, o := range p.Origins {
+		if o.Field == field {
+			return o, true
+		}
+	}
+	return Origin{}, false
+}
+
+// Table returns the provenance as a text table.
+func (p *Provenance) Table() string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "FIELD\tSOURCE")
+	for _, o := range p.Origins {
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", o.Field, o)
+	}
+	_ = w.Flush()
+	return buf.String()
+}
+
+// Fields returns the provenance as log fields, one field per config field.
+func (p *Provenance) Fields() []log.Field {
+	fields := make([]log.Field, 0, len(p.Origins))
+	for _, o := range p.Origins {
+		fields = append(fields, log.Stringer(o.Field, o))
+	}
+	return fields
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cornelk/gotokit/envfile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadProvenance(t *testing.T) {
+	type Database struct {
+		Host     string `env:"HOST"`
+		Port     int    `env:"PORT" envDefault:"5432"`
+		User     string `env:"USER"`
+		Password string `env:"PASSWORD"`
+		Database string `env:"DATABASE"`
+		Driver   string `env:"DRIVER"`
+	}
+
+	type myConfig struct {
+		Database Database `envPrefix:"DATABASE_"`
+	}
+
+	tmpdir := t.TempDir()
+	passwordFile := filepath.Join(tmpdir, "password")
+	require.NoError(t, os.WriteFile(passwordFile, []byte("hunter2\n"), 0o600))
+	envFile := filepath.Join(tmpdir, ".env")
+	require.NoError(t, os.WriteFile(envFile, []byte("DATABASE_DATABASE=app\n"), 0o600))
+
+	t.Setenv("DATABASE_DATABASE", "")
+	envfile.LoadFiles(envFile)
+
+	t.Setenv("DATABASE_HOST", "defaulthost")
+	t.Setenv("TESTAPP_DATABASE_HOST", "localhost")
+	t.Setenv("DATABASE_PASSWORD_FILE", passwordFile)
+
+	var provenance Provenance
+	opts := Options{
+		Prefixes:    []string{"", "testapp"},
+		SecretFiles: true,
+		FlagSet:     flag.NewFlagSet("test", flag.ContinueOnError),
+		Args:        []string{"-database-user", "admin"},
+		Provenance:  &provenance,
+	}
+
+	var cfg myConfig
+	require.NoError(t, Read(&cfg, opts))
+	assert.Equal(t, "localhost", cfg.Database.Host)
+	assert.Equal(t, 5432, cfg.Database.Port)
+
+	expected := []Origin{
+		{Field: "Database.Host", Kind: OriginEnv, Variable: "TESTAPP_DATABASE_HOST", Prefix: "TESTAPP_"},
+		{Field: "Database.Port", Kind: OriginDefault},
+		{Field: "Database.User", Kind: OriginFlag, Variable: "TESTAPP_DATABASE_USER", Prefix: "TESTAPP_",
+			Flag: "database-user"},
+		{Field: "Database.Password", Kind: OriginSecretFile, Variable: "DATABASE_PASSWORD_FILE",
+			File: passwordFile},
+		{Field: "Database.Database", Kind: OriginEnvFile, Variable: "DATABASE_DATABASE", File: envFile},
+		{Field: "Database.Driver", Kind: OriginUnset},
+	}
+	assert.Equal(t, expected, provenance.Origins)
+
+	origin, ok := provenance.Lookup("Database.Host")
+	require.True(t, ok)
+	assert.Equal(t, "env TESTAPP_DATABASE_HOST", origin.String())
+
+	table := provenance.Table()
+	assert.Contains(t, table, "Database.User      flag -database-user")
+	assert.Contains(t, table, "Database.Password  secret file "+passwordFile+" (DATABASE_PASSWORD_FILE)")
+
+	fields := provenance.Fields()
+	require.Len(t, fields, len(expected))
+	assert.Equal(t, "Database.Port", fields[1].Key)
+	assert.Equal(t, "default", fields[1].Value.String())
+}
+
+func TestReadPrefixDefaults(t *testing.T) {
+	type myConfig struct {
+		Host string `env:"HOST" envDefault:"localhost"`
+	}
+
+	// a default value does not override a value of an earlier prefix
+	t.Setenv("HOST", "defaulthost")
+
+	var cfg myConfig
+	require.NoError(t, Read(&cfg, Options{Prefixes: []string{"", "testapp"}}))
+	assert.Equal(t, "defaulthost", cfg.Host)
+}
@@ -2,38 +2,213 @@
 package config
 
 import (
+	"context"
 	"fmt"
+	"maps"
+	"os"
 	"strings"
 
-	"github.com/caarlos0/env/v11"
+	envparser "github.com/caarlos0/env/v11"
+	"github.com/cornelk/gotokit/envfile"
 )
 
-// Read reads the environment variables for the given prefix and unmarshals it into the config object.
+// Read reads the environment variables for the given prefixes and unmarshals them into the config object.
 // To support both prefixed and non prefixed envs at the same time it is recommended to call the function
 // with an empty first prefix and a second set prefix. Only environment variables that exist will
 // set a field in the config. This way, an environment variable set without a prefix can be overwritten
 // by an environment variable with a prefix.
 func Read(config any, opts Options) error {
-	// fall back to a default prefix if none are provided
-	if len(opts.Prefixes) == 0 {
-		opts.Prefixes = []string{""}
+	return ReadContext(context.Background(), config, opts)
+}
+
+// ReadContext reads the config like Read, the context is passed to the configured sources.
+func ReadContext(ctx context.Context, config any, opts Options) error {
+	fields, err := parseFields(config)
+	if err != nil {
+		return err
+	}
+
+	prefixes := normalizePrefixes(opts.Prefixes)
+	environment, overrides := readEnvironment(opts)
+
+	if opts.Strict {
+		if err := checkUnknownVariables(environment, knownVariables(fields, prefixes, opts), prefixes); err != nil {
+			return err
+		}
 	}
 
-	for _, prefix := range opts.Prefixes {
+	if err := applySources(ctx, environment, overrides, opts.Sources); err != nil {
+		return err
+	}
+	if err := applyOverrides(environment, overrides, fields, prefixes, opts); err != nil {
+		return err
+	}
+
+	values, origins := mergePrefixes(environment, overrides, prefixes)
+	if err := applyEnvironmentDefaults(values, fields, opts.Environment); err != nil {
+		return err
+	}
+
+	parsers := defaultParsers()
+	maps.Copy(parsers, opts.FuncMap)
+	if err := normalizeTextValues(values, fields, parsers); err != nil {
+		return err
+	}
+
+	envOpts := envparser.Options{
+		Environment: values,
+		FuncMap:     parsers,
+	}
+	if err := envparser.ParseWithOptions(config, envOpts); err != nil {
+		return fmt.Errorf("reading config from env: %w", err)
+	}
+
+	if opts.Provenance != nil {
+		opts.Provenance.Origins = fieldOrigins(fields, values, origins)
+	}
+	return nil
+}
+
+// readEnvironment returns the environment variables to read the config from and the
+// origins of the variables that were loaded from env files.
+func readEnvironment(opts Options) (map[string]string, map[string]Origin) {
+	overrides := map[string]Origin{}
+	if opts.Variables != nil {
+		return maps.Clone(opts.Variables), overrides
+	}
+
+	environment := envparser.ToMap(os.Environ())
+	for variable := range environment {
+		if file, ok := envfile.Origin(variable); ok {
+			overrides[variable] = Origin{Kind: OriginEnvFile, Variable: variable, File: file}
+		}
+	}
+	return environment, overrides
+}
+
+// applyOverrides applies the values of command line flags and secret files to the environment
+// and sets the origins of the overridden variables.
+func applyOverrides(environment map[string]string, overrides map[string]Origin, fields []field,
+	prefixes []string, opts Options) error {
+
+	if opts.FlagSet != nil {
+		flags, err := applyFlags(environment, fields, prefixes, opts)
+		if err != nil {
+			return err
+		}
+		for variable, name := range flags {
+			overrides[variable] = Origin{Kind: OriginFlag, Variable: variable, Flag: name}
+		}
+	}
+
+	if opts.SecretFiles {
+		secrets, err := resolveSecretFiles(environment, fields, prefixes, opts)
+		if err != nil {
+			return err
+		}
+		for _, secret := range secrets {
+			overrides[secret.Key] = Origin{Kind: OriginSecretFile, Variable: secret.Variable, File: secret.Path}
+			if opts.OnSecretFile != nil {
+				opts.OnSecretFile(secret)
+			}
+		}
+	}
+
+	return nil
+}
+
+// mergePrefixes returns the environment with the prefixes removed from the variable names
+// and the origins of all values. Variables of later prefixes override the ones of earlier
+// prefixes, variables that do not have any of the prefixes are ignored.
+func mergePrefixes(environment map[string]string, overrides map[string]Origin,
+	prefixes []string) (map[string]string, map[string]Origin) {
+
+	values := make(map[string]string, len(environment))
+	origins := make(map[string]Origin, len(environment))
+
+	for _, prefix := range prefixes {
+		for variable, value := range environment {
+			key, ok := strings.CutPrefix(variable, prefix)
+			if !ok || key == "" {
+				continue
+			}
+
+			o, ok := overrides[variable]
+			if !ok {
+				o = Origin{Kind: OriginEnv, Variable: variable}
+			}
+			o.Prefix = prefix
+
+			values[key] = value
+			origins[key] = o
+		}
+	}
+
+	return values, origins
+}
+
+// fieldOrigins returns the origins of the values of all fields.
+func fieldOrigins(fields []field, values map[string]string, origins map[string]Origin) []Origin {
+	result := make([]Origin, 0, len(fields))
+
+	for _, f := range fields {
+		o, ok := origins[f.key]
+		value := values[f.key]
+
+		switch {
+		case ok && (value != "" || !f.hasDefault):
+		case f.hasDefault:
+			o = Origin{Kind: OriginDefault}
+
+		default:
+			o = Origin{Kind: OriginUnset}
+		}
+
+		o.Field = f.path
+		result = append(result, o)
+	}
+
+	return result
+}
+
+// prefixedKeys returns the keys of all fields combined with all prefixes.
+func prefixedKeys(fields []field, prefixes []string) []string {
+	keys := make([]string, 0, len(prefixes)*len(fields))
+	for _, prefix := range prefixes {
+		for _, f := range fields {
+			keys = append(keys, prefix+f.key)
+		}
+	}
+	return keys
+}
+
+// knownVariables returns the names of all environment variables that are read for the fields.
+func knownVariables(fields []field, prefixes []string, opts Options) []string {
+	known := prefixedKeys(fields, prefixes)
+	if opts.SecretFiles {
+		for _, key := range prefixedKeys(fields, prefixes) {
+			known = append(known, key+secretFileSuffix)
+		}
+	}
+	return known
+}
+
+// normalizePrefixes returns the prefixes in upper case and ending with an underscore.
+// It falls back to an empty prefix if none are provided.
+func normalizePrefixes(prefixes []string) []string {
+	if len(prefixes) == 0 {
+		return []string{""}
+	}
+
+	normalized := make([]string, 0, len(prefixes))
+	for _, prefix := range prefixes {
 		if prefix != "" {
 			if !strings.HasSuffix(prefix, "_") {
 				prefix += "_"
 			}
 			prefix = strings.ToUpper(prefix)
 		}
-
-		envOpts := env.Options{
-			Prefix:  prefix,
-			FuncMap: opts.FuncMap,
-		}
-		if err := env.ParseWithOptions(config, envOpts); err != nil {
-			return fmt.Errorf("reading config from env: %w", err)
-		}
+		normalized = append(normalized, prefix)
 	}
-	return nil
+	return normalized
 }
@@ -66,3 +66,26 @@ func TestReaderFuncMap(t *testing.T) {
 	assert.Equal(t, "admin", cfg.Foo.name)
 	assert.Equal(t, 1, called)
 }
+
+func TestReaderVariables(t *testing.T) {
+	t.Parallel()
+
+	type myConfig struct {
+		Host string `env:"VARIABLES_HOST"`
+		Port int    `env:"VARIABLES_PORT" envDefault:"80"`
+	}
+
+	var provenance Provenance
+	var cfg myConfig
+	opts := Options{
+		Variables:  map[string]string{"VARIABLES_HOST": "maphost"},
+		Provenance: &provenance,
+	}
+	require.NoError(t, Read(&cfg, opts))
+	assert.Equal(t, "maphost", cfg.Host)
+	assert.Equal(t, 80, cfg.Port)
+
+	origin, ok := provenance.Lookup("Host")
+	require.True(t, ok)
+	assert.Equal(t, OriginEnv, origin.Kind)
+}
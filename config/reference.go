@@ -0,0 +1,181 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrReferenceOutdated is returned when a reference file does not match the config struct.
+var ErrReferenceOutdated = errors.New("reference file is outdated")
+
+// Variable describes an environment variable that is read into a config struct.
+type Variable struct {
+	Names       []string // environment variable names in order of increasing precedence
+	Field       string   // path of the struct field, for example Database.Host
+	Type        string   // Go type of the struct field
+	Default     string   // default value, empty for secrets
+	Required    bool
+	Secret      bool
+	Description string // description from the desc tag
+
+	// EnvironmentDefaults are the defaults of specific environments, empty for secrets.
+	EnvironmentDefaults []EnvironmentDefault
+}
+
+// Reference describes all environment variables that are read into a config struct.
+// It can be rendered as Markdown documentation or as .env.example file.
+type Reference struct {
+	Variables []Variable
+}
+
+// NewReference returns the reference of all environment variables that Read uses for the
+// given config struct and options. A description for every field can be set using the
+// desc tag, for example `env:"HOST" desc:"Database host name"`.
+func NewReference(config any, opts Options) (*Reference, error) {
+	fields, err := parseFields(config)
+	if err != nil {
+		return nil, err
+	}
+
+	prefixes := normalizePrefixes(opts.Prefixes)
+	ref := &Reference{
+		Variables: make([]Variable, 0, len(fields)),
+	}
+
+	for _, f := range fields {
+		names := make([]string, 0, len(prefixes))
+		for _, prefix := range prefixes {
+			names = append(names, prefix+f.key)
+		}
+
+		v := Variable{
+			Names:       names,
+			Field:       f.path,
+			Type:        f.typ.String(),
+			Required:    f.required,
+			Secret:      f.secret,
+			Description: f.tag.Get(tagDesc),
+		}
+		if !f.secret {
+			v.Default = f.defaultValue
+			if v.EnvironmentDefaults, err = environmentDefaults(f); err != nil {
+				return nil, err
+			}
+		}
+		ref.Variables = append(ref.Variables, v)
+	}
+
+	return ref, nil
+}
+
+// Markdown returns the reference as Markdown table.
+func (r *Reference) Markdown() string {
+	var buf bytes.Buffer
+	buf.WriteString("| Variable | Type | Default | Required | Secret | Description |\n")
+	buf.WriteString("|----------|------|---------|----------|--------|-------------|\n")
+
+	for _, v := range r.Variables {
+		names := make([]string, 0, len(v.Names))
+		for _, name := range v.Names {
+			names = append(names, "`"+name+"`")
+		}
+
+		var defaults []string
+		if v.Default != "" {
+			defaults = append(defaults, "`"+escapeMarkdown(v.Default)+"`")
+		}
+		for _, def := range v.EnvironmentDefaults {
+			defaults = append(defaults, def.Environment.String()+": `"+escapeMarkdown(def.Value)+"`")
+		}
+
+		fmt.Fprintf(&buf, "| %s | `%s` | %s | %s | %s | %s |\n",
+			strings.Join(names, "<br>"), v.Type, strings.Join(defaults, "<br>"), yesNo(v.Required), yesNo(v.Secret),
+			escapeMarkdown(v.Description))
+	}
+
+	return buf.String()
+}
+
+// EnvExample returns the reference as commented .env.example file. Every variable is
+// listed with the name of the highest precedence prefix and its default value.
+func (r *Reference) EnvExample() string {
+	var buf bytes.Buffer
+
+	for i, v := range r.Variables {
+		if i > 0 {
+			buf.WriteByte('\n')
+		}
+
+		if v.Description != "" {
+			fmt.Fprintf(&buf, "# %s\n", v.Description)
+		}
+
+		attributes := []string{v.Type}
+		if v.Required {
+			attributes = append(attributes, "required")
+		}
+		if v.Secret {
+			attributes = append(attributes, "secret")
+		}
+		fmt.Fprintf(&buf, "# Type: %s\n", strings.Join(attributes, ", "))
+		if len(v.EnvironmentDefaults) > 0 {
+			fmt.Fprintf(&buf, "# Environment defaults: %s\n", formatEnvironmentDefaults(v.EnvironmentDefaults))
+		}
+
+		if len(v.Names) > 1 {
+			fmt.Fprintf(&buf, "# Also read as: %s\n", strings.Join(v.Names[:len(v.Names)-1], ", "))
+		}
+		fmt.Fprintf(&buf, "%s=%s\n", v.Names[len(v.Names)-1], v.Default)
+	}
+
+	return buf.String()
+}
+
+// WriteFile writes the reference to the given file. Files with a .md extension are
+// written as Markdown, all others in .env format. This function is intended to be
+// used by a generator that is run using go generate.
+func (r *Reference) WriteFile(path string) error {
+	if err := os.WriteFile(path, []byte(r.render(path)), 0o644); err != nil {
+		return fmt.Errorf("writing reference file: %w", err)
+	}
+	return nil
+}
+
+// VerifyFile checks that the given file matches the generated reference. It returns an
+// error wrapping ErrReferenceOutdated if the file content differs, which allows tests
+// to detect undocumented changes of the config struct.
+func (r *Reference) VerifyFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading reference file: %w", err)
+	}
+
+	if string(data) != r.render(path) {
+		return fmt.Errorf("%w: %s", ErrReferenceOutdated, path)
+	}
+	return nil
+}
+
+func (r *Reference) render(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".md") {
+		return r.Markdown()
+	}
+	return r.EnvExample()
+}
+
+// escapeMarkdown escapes characters that break a Markdown table cell.
+func escapeMarkdown(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
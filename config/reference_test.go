@@ -0,0 +1,81 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cornelk/gotokit/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type referenceConfig struct {
+	Database struct {
+		Host     string `env:"HOST" envDefault:"localhost" envDefault.prod:"db" desc:"Database host name"`
+		Password string `env:"PASSWORD,required" envDefault:"secret"`
+	} `envPrefix:"DATABASE_"`
+	Debug bool `env:"DEBUG" desc:"Enable debug | verbose mode"`
+}
+
+func TestReference(t *testing.T) {
+	ref, err := NewReference(&referenceConfig{}, Options{Prefixes: []string{"", "testapp"}})
+	require.NoError(t, err)
+	require.Len(t, ref.Variables, 3)
+
+	host := ref.Variables[0]
+	assert.Equal(t, []string{"DATABASE_HOST", "TESTAPP_DATABASE_HOST"}, host.Names)
+	assert.Equal(t, "Database.Host", host.Field)
+	assert.Equal(t, "string", host.Type)
+	assert.Equal(t, "localhost", host.Default)
+	assert.Equal(t, []EnvironmentDefault{{Environment: env.Production, Value: "db"}}, host.EnvironmentDefaults)
+
+	password := ref.Variables[1]
+	assert.True(t, password.Required)
+	assert.True(t, password.Secret)
+	assert.Empty(t, password.Default)
+
+	expectedMarkdown := "| Variable | Type | Default | Required | Secret | Description |\n" +
+		"|----------|------|---------|----------|--------|-------------|\n" +
+		"| `DATABASE_HOST`<br>`TESTAPP_DATABASE_HOST` | `string` | `localhost`<br>prod: `db` | no | no | Database host name |\n" +
+		"| `DATABASE_PASSWORD`<br>`TESTAPP_DATABASE_PASSWORD` | `string` |  | yes | yes |  |\n" +
+		"| `DEBUG`<br>`TESTAPP_DEBUG` | `bool` |  | no | no | Enable debug \\| verbose mode |\n"
+	assert.Equal(t, expectedMarkdown, ref.Markdown())
+
+	expectedExample := "# Database host name\n" +
+		"# Type: string\n" +
+		"# Environment defaults: prod: db\n" +
+		"# Also read as: DATABASE_HOST\n" +
+		"TESTAPP_DATABASE_HOST=localhost\n" +
+		"\n" +
+		"# Type: string, required, secret\n" +
+		"# Also read as: DATABASE_PASSWORD\n" +
+		"TESTAPP_DATABASE_PASSWORD=\n" +
+		"\n" +
+		"# Enable debug | verbose mode\n" +
+		"# Type: bool\n" +
+		"# Also read as: DEBUG\n" +
+		"TESTAPP_DEBUG=\n"
+	assert.Equal(t, expectedExample, ref.EnvExample())
+}
+
+func TestReferenceFiles(t *testing.T) {
+	ref, err := NewReference(&referenceConfig{}, Options{})
+	require.NoError(t, err)
+
+	tmpdir := t.TempDir()
+	for _, name := range []string{"CONFIG.md", ".env.example"} {
+		file := filepath.Join(tmpdir, name)
+		require.ErrorIs(t, ref.VerifyFile(file), os.ErrNotExist)
+
+		require.NoError(t, ref.WriteFile(file))
+		require.NoError(t, ref.VerifyFile(file))
+
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "DATABASE_HOST")
+
+		require.NoError(t, os.WriteFile(file, []byte("outdated"), 0o644))
+		require.ErrorIs(t, ref.VerifyFile(file), ErrReferenceOutdated)
+	}
+}
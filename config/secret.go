@@ -0,0 +1,107 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	// DefaultMaxSecretFileSize is the maximum size of a secret file if no other limit is set.
+	DefaultMaxSecretFileSize = 64 * 1024
+
+	secretFileSuffix = "_FILE"
+	secretFileScheme = "file://"
+)
+
+// ErrSecretFileTooLarge is returned when a secret file exceeds the configured size limit.
+var ErrSecretFileTooLarge = errors.New("secret file too large")
+
+// SecretFile describes a config value that was read from a file.
+type SecretFile struct {
+	Key      string // environment variable name that the value was read for
+	Variable string // environment variable that referenced the file
+	Path     string // path of the file that supplied the value
+}
+
+// resolveSecretFiles replaces the values of the fields in the environment by the content
+// of the file that they reference and returns all resolved files. A file can be referenced
+// by a variable with a _FILE suffix, for example DATABASE_PASSWORD_FILE. Values of secret
+// fields can also reference a file using the file:// scheme, values of other fields are
+// kept as they are, as they can be regular URLs. A directly set value takes precedence
+// over a _FILE variable.
+func resolveSecretFiles(environment map[string]string, fields []field, prefixes []string,
+	opts Options) ([]SecretFile, error) {
+
+	maxSize := opts.MaxSecretFileSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSecretFileSize
+	}
+
+	var secrets []SecretFile
+	for _, prefix := range prefixes {
+		for _, f := range fields {
+			secret, ok := secretFile(environment, prefix+f.key, f.secret)
+			if !ok {
+				continue
+			}
+
+			content, err := readSecretFile(secret.Path, maxSize)
+			if err != nil {
+				return nil, fmt.Errorf("reading secret file for %s from %s: %w", secret.Key, secret.Variable, err)
+			}
+
+			environment[secret.Key] = content
+			secrets = append(secrets, secret)
+		}
+	}
+
+	return secrets, nil
+}
+
+// secretFile returns the file that the value of the given key references. The file://
+// scheme is only supported for secret fields.
+func secretFile(environment map[string]string, key string, secret bool) (SecretFile, bool) {
+	file := SecretFile{
+		Key:      key,
+		Variable: key,
+	}
+
+	value, ok := environment[key]
+	switch {
+	case ok && secret && strings.HasPrefix(value, secretFileScheme):
+		file.Path = strings.TrimPrefix(value, secretFileScheme)
+
+	case !ok && environment[key+secretFileSuffix] != "":
+		file.Variable = key + secretFileSuffix
+		file.Path = environment[file.Variable]
+
+	default:
+		return SecretFile{}, false
+	}
+
+	return file, true
+}
+
+// readSecretFile reads the content of the given file and trims trailing newlines.
+func readSecretFile(path string, maxSize int64) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("opening file: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	data, err := io.ReadAll(io.LimitReader(f, maxSize+1))
+	if err != nil {
+		return "", fmt.Errorf("reading file: %w", err)
+	}
+	if int64(len(data)) > maxSize {
+		return "", fmt.Errorf("%w: limit is %d bytes", ErrSecretFileTooLarge, maxSize)
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
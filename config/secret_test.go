@@ -0,0 +1,92 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadSecretFiles(t *testing.T) {
+	type Database struct {
+		User     string `env:"USER" secret:"true"`
+		Password string `env:"PASSWORD"`
+	}
+
+	type myConfig struct {
+		Database   Database `envPrefix:"DATABASE_"`
+		StorageURL string   `env:"STORAGE_URL"`
+	}
+
+	tmpdir := t.TempDir()
+	passwordFile := filepath.Join(tmpdir, "password")
+	require.NoError(t, os.WriteFile(passwordFile, []byte("hunter2\n"), 0o600))
+	userFile := filepath.Join(tmpdir, "user")
+	require.NoError(t, os.WriteFile(userFile, []byte("admin\r\n"), 0o600))
+
+	t.Setenv("DATABASE_PASSWORD_FILE", passwordFile)
+	t.Setenv("TESTAPP_DATABASE_USER", "file://"+userFile)
+	t.Setenv("STORAGE_URL", "file://"+tmpdir)
+
+	var secrets []SecretFile
+	opts := Options{
+		Prefixes:    []string{"", "testapp"},
+		SecretFiles: true,
+		OnSecretFile: func(secret SecretFile) {
+			secrets = append(secrets, secret)
+		},
+	}
+
+	var cfg myConfig
+	require.NoError(t, Read(&cfg, opts))
+	assert.Equal(t, "hunter2", cfg.Database.Password)
+	assert.Equal(t, "admin", cfg.Database.User)
+	// the file scheme is only resolved for secret fields
+	assert.Equal(t, "file://"+tmpdir, cfg.StorageURL)
+
+	expected := []SecretFile{
+		{Key: "DATABASE_PASSWORD", Variable: "DATABASE_PASSWORD_FILE", Path: passwordFile},
+		{Key: "TESTAPP_DATABASE_USER", Variable: "TESTAPP_DATABASE_USER", Path: userFile},
+	}
+	assert.Equal(t, expected, secrets)
+
+	// a directly set value takes precedence over the file
+	t.Setenv("DATABASE_PASSWORD", "direct")
+	cfg = myConfig{}
+	require.NoError(t, Read(&cfg, opts))
+	assert.Equal(t, "direct", cfg.Database.Password)
+
+	// files are only read if enabled
+	cfg = myConfig{}
+	require.NoError(t, Read(&cfg, Options{Prefixes: opts.Prefixes}))
+	assert.Equal(t, "file://"+userFile, cfg.Database.User)
+}
+
+func TestReadSecretFilesErrors(t *testing.T) {
+	type myConfig struct {
+		Password string `env:"PASSWORD"`
+	}
+
+	tmpdir := t.TempDir()
+	file := filepath.Join(tmpdir, "password")
+	require.NoError(t, os.WriteFile(file, []byte(strings.Repeat("a", 11)), 0o600))
+
+	t.Setenv("PASSWORD_FILE", file)
+	opts := Options{
+		SecretFiles:       true,
+		MaxSecretFileSize: 10,
+	}
+
+	var cfg myConfig
+	require.ErrorIs(t, Read(&cfg, opts), ErrSecretFileTooLarge)
+
+	opts.MaxSecretFileSize = 11
+	require.NoError(t, Read(&cfg, opts))
+	assert.Equal(t, strings.Repeat("a", 11), cfg.Password)
+
+	t.Setenv("PASSWORD_FILE", filepath.Join(tmpdir, "missing"))
+	require.ErrorIs(t, Read(&cfg, opts), os.ErrNotExist)
+}
@@ -0,0 +1,238 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSourceValue is returned when a source contains a value that can not be
+// used as a config value.
+var ErrInvalidSourceValue = errors.New("invalid source value")
+
+// Source provides config values from a key/value store, for example a central config
+// service. The keys are environment variable names including any prefix.
+type Source interface {
+	// Values returns all keys and their values.
+	Values(ctx context.Context) (map[string]string, error)
+	// Watch calls the given function whenever the values change. It blocks until the
+	// context is done.
+	Watch(ctx context.Context, onChange func()) error
+}
+
+// applySources sets the values of all sources in the environment. Sources are layered
+// between env files and the environment: values of later sources override earlier ones
+// and values loaded from env files, but not variables set directly in the environment.
+func applySources(ctx context.Context, environment map[string]string, overrides map[string]Origin,
+	sources []Source) error {
+
+	for _, source := range sources {
+		values, err := source.Values(ctx)
+		if err != nil {
+			return fmt.Errorf("reading values of source %s: %w", sourceName(source), err)
+		}
+
+		for key, value := range values {
+			if isSetInEnvironment(environment, overrides, key) {
+				continue
+			}
+
+			environment[key] = value
+			overrides[key] = Origin{Kind: OriginSource, Variable: key, Source: sourceName(source)}
+		}
+	}
+
+	return nil
+}
+
+// isSetInEnvironment returns whether the variable is set directly in the environment,
+// not by an env file or a previous source.
+func isSetInEnvironment(environment map[string]string, overrides map[string]Origin, key string) bool {
+	if _, ok := environment[key]; !ok {
+		return false
+	}
+	_, ok := overrides[key]
+	return !ok
+}
+
+// sourceName returns the name of a source for reporting.
+func sourceName(source Source) string {
+	if s, ok := source.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%T", source)
+}
+
+// HTTPSource reads config values from an HTTP endpoint that returns a JSON object
+// of keys and values. Values can be strings, numbers or booleans.
+type HTTPSource struct {
+	URL          string
+	Client       *http.Client  // defaults to http.DefaultClient
+	PollInterval time.Duration // interval to check for changes, defaults to DefaultPollInterval
+}
+
+// NewHTTPSource returns a new HTTP source for the given URL.
+func NewHTTPSource(url string) *HTTPSource {
+	return &HTTPSource{
+		URL: url,
+	}
+}
+
+// String returns the URL of the source.
+func (s *HTTPSource) String() string {
+	return s.URL
+}
+
+// Values returns all keys and their values.
+func (s *HTTPSource) Values(ctx context.Context) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	var data map[string]any
+	decoder := json.NewDecoder(resp.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&data); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+
+	values := make(map[string]string, len(data))
+	for key, value := range data {
+		switch v := value.(type) {
+		case string:
+			values[key] = v
+		case json.Number, bool:
+			values[key] = fmt.Sprint(v)
+		default:
+			return nil, fmt.Errorf("%w: key %s has type %T", ErrInvalidSourceValue, key, value)
+		}
+	}
+	return values, nil
+}
+
+// Watch polls the endpoint for changes and calls the given function whenever the
+// values change. It blocks until the context is done.
+func (s *HTTPSource) Watch(ctx context.Context, onChange func()) error {
+	return pollValues(ctx, s, s.PollInterval, onChange)
+}
+
+// DirectorySource reads config values from a directory that contains one file per key,
+// as used for mounted Kubernetes ConfigMaps. Hidden files are ignored, trailing newlines
+// of the values are trimmed.
+type DirectorySource struct {
+	Path         string
+	PollInterval time.Duration // interval to check for changes, defaults to DefaultPollInterval
+	MaxFileSize  int64         // maximum size of a file, defaults to DefaultMaxSecretFileSize
+}
+
+// NewDirectorySource returns a new directory source for the given path.
+func NewDirectorySource(path string) *DirectorySource {
+	return &DirectorySource{
+		Path: path,
+	}
+}
+
+// String returns the path of the source.
+func (s *DirectorySource) String() string {
+	return s.Path
+}
+
+// Values returns all keys and their values.
+func (s *DirectorySource) Values(_ context.Context) (map[string]string, error) {
+	entries, err := os.ReadDir(s.Path)
+	if err != nil {
+		return nil, fmt.Errorf("reading directory: %w", err)
+	}
+
+	maxSize := s.MaxFileSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSecretFileSize
+	}
+
+	values := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		path := filepath.Join(s.Path, name)
+		info, err := os.Stat(path) // follow symlinks
+		if err != nil {
+			return nil, fmt.Errorf("reading file info: %w", err)
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+
+		value, err := readSecretFile(path, maxSize)
+		if err != nil {
+			return nil, fmt.Errorf("reading value of key %s: %w", name, err)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// Watch polls the directory for changes and calls the given function whenever the
+// values change. It blocks until the context is done.
+func (s *DirectorySource) Watch(ctx context.Context, onChange func()) error {
+	return pollValues(ctx, s, s.PollInterval, onChange)
+}
+
+// pollValues polls the values of the source in the given interval and calls the given
+// function whenever the values change. Failed polls are ignored and retried in the
+// next interval.
+func pollValues(ctx context.Context, source Source, interval time.Duration, onChange func()) error {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	// a failed initial poll reports the first successful poll as change
+	previous, _ := source.Values(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case <-ticker.C:
+			current, err := source.Values(ctx)
+			if err != nil {
+				continue
+			}
+			if !maps.Equal(previous, current) {
+				previous = current
+				onChange()
+			}
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPSource(t *testing.T) {
+	var body atomic.Value
+	body.Store(`{"DATABASE_HOST": "remotehost", "DATABASE_PORT": 5432, "DEBUG": true}`)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(body.Load().(string)))
+	}))
+	defer server.Close()
+
+	source := NewHTTPSource(server.URL)
+	source.PollInterval = 10 * time.Millisecond
+
+	ctx := context.Background()
+	values, err := source.Values(ctx)
+	require.NoError(t, err)
+	expected := map[string]string{
+		"DATABASE_HOST": "remotehost",
+		"DATABASE_PORT": "5432",
+		"DEBUG":         "true",
+	}
+	assert.Equal(t, expected, values)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	changed := make(chan struct{}, 1)
+	go func() {
+		_ = source.Watch(ctx, func() {
+			changed <- struct{}{}
+		})
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	body.Store(`{"DATABASE_HOST": "otherhost"}`)
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("change was not detected")
+	}
+
+	body.Store(`{"DATABASE_HOST": {"nested": true}}`)
+	_, err = source.Values(ctx)
+	require.ErrorIs(t, err, ErrInvalidSourceValue)
+}
+
+func TestDirectorySource(t *testing.T) {
+	tmpdir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpdir, "DATABASE_HOST"), []byte("dirhost\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpdir, ".hidden"), []byte("hidden"), 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(tmpdir, "..data"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpdir, "..data", "DATABASE_PORT"), []byte("1234"), 0o644))
+	require.NoError(t, os.Symlink(filepath.Join("..data", "DATABASE_PORT"), filepath.Join(tmpdir, "DATABASE_PORT")))
+
+	source := NewDirectorySource(tmpdir)
+	values, err := source.Values(context.Background())
+	require.NoError(t, err)
+	expected := map[string]string{
+		"DATABASE_HOST": "dirhost",
+		"DATABASE_PORT": "1234",
+	}
+	assert.Equal(t, expected, values)
+
+	source.MaxFileSize = 4
+	_, err = source.Values(context.Background())
+	require.ErrorIs(t, err, ErrSecretFileTooLarge)
+
+	_, err = NewDirectorySource(filepath.Join(tmpdir, "missing")).Values(context.Background())
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestReadSources(t *testing.T) {
+	type myConfig struct {
+		Host string `env:"DATABASE_HOST"`
+		Port int    `env:"DATABASE_PORT"`
+		User string `env:"DATABASE_USER"`
+	}
+
+	first := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(first, "DATABASE_HOST"), []byte("firsthost"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(first, "DATABASE_PORT"), []byte("1"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(first, "DATABASE_USER"), []byte("firstuser"), 0o644))
+	second := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(second, "DATABASE_PORT"), []byte("2"), 0o644))
+
+	t.Setenv("DATABASE_USER", "envuser")
+
+	var provenance Provenance
+	opts := Options{
+		Sources:    []Source{NewDirectorySource(first), NewDirectorySource(second)},
+		Provenance: &provenance,
+	}
+
+	var cfg myConfig
+	require.NoError(t, ReadContext(context.Background(), &cfg, opts))
+	assert.Equal(t, "firsthost", cfg.Host)
+	assert.Equal(t, 2, cfg.Port)
+	assert.Equal(t, "envuser", cfg.User)
+
+	origin, ok := provenance.Lookup("Port")
+	require.True(t, ok)
+	assert.Equal(t, "source "+second+" (DATABASE_PORT)", origin.String())
+
+	opts.Sources = append(opts.Sources, NewDirectorySource(filepath.Join(first, "missing")))
+	require.Error(t, Read(&cfg, opts))
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/cornelk/gotokit/set"
+)
+
+// ErrUnknownVariables is returned in strict mode when environment variables with one
+// of the configured prefixes do not map to any config field.
+var ErrUnknownVariables = errors.New("unknown environment variables")
+
+// UnknownVariable describes an environment variable that does not map to any config field.
+type UnknownVariable struct {
+	Name       string // name of the environment variable
+	Suggestion string // most similar known variable name, empty if none is similar
+}
+
+// UnknownVariablesError contains all environment variables that were found in strict mode
+// that do not map to any config field.
+type UnknownVariablesError struct {
+	Variables []UnknownVariable
+}
+
+// Error returns the error message including suggestions for misspelled variables.
+func (e *UnknownVariablesError) Error() string {
+	messages := make([]string, 0, len(e.Variables))
+	for _, v := range e.Variables {
+		msg := v.Name
+		if v.Suggestion != "" {
+			msg += " (did you mean " + v.Suggestion + "?)"
+		}
+		messages = append(messages, msg)
+	}
+	return ErrUnknownVariables.Error() + ": " + strings.Join(messages, ", ")
+}
+
+// Is reports whether the target error is ErrUnknownVariables.
+func (e *UnknownVariablesError) Is(target error) bool {
+	return target == ErrUnknownVariables
+}
+
+// checkUnknownVariables returns an error listing all environment variables that start
+// with one of the non-empty prefixes but are not part of the known variable names.
+func checkUnknownVariables(environment map[string]string, known []string, prefixes []string) error {
+	var unknown []UnknownVariable
+	knownSet := set.NewFromSlice(known)
+
+	for variable := range environment {
+		if knownSet.Contains(variable) {
+			continue
+		}
+
+		for _, prefix := range prefixes {
+			if prefix == "" || !strings.HasPrefix(variable, prefix) {
+				continue
+			}
+
+			unknown = append(unknown, UnknownVariable{
+				Name:       variable,
+				Suggestion: suggestVariable(variable, known),
+			})
+			break
+		}
+	}
+
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	slices.SortFunc(unknown, func(a, b UnknownVariable) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return &UnknownVariablesError{Variables: unknown}
+}
+
+// suggestVariable returns the known variable name with the smallest edit distance to
+// the given name. Only names that are within a distance of a third of the name length
+// are considered to be similar.
+func suggestVariable(name string, known []string) string {
+	best := max(1, len(name)/3) + 1
+	var suggestion string
+
+	for _, candidate := range known {
+		if distance := editDistance(name, candidate); distance < best {
+			best = distance
+			suggestion = candidate
+		}
+	}
+	return suggestion
+}
+
+// editDistance returns the Levenshtein distance of the two strings.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
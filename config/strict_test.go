@@ -0,0 +1,56 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadStrict(t *testing.T) {
+	type Database struct {
+		Host     string `env:"HOST"`
+		Password string `env:"PASSWORD"`
+	}
+
+	type myConfig struct {
+		Database Database `envPrefix:"DATABASE_"`
+	}
+
+	t.Setenv("TESTAPP_DATABASE_HOST", "localhost")
+	t.Setenv("TESTAPP_DATABASE_PASSWORD_FILE", "")
+	t.Setenv("DATABASE_UNKNOWN", "ignored without prefix")
+
+	opts := Options{
+		Prefixes:    []string{"", "testapp"},
+		SecretFiles: true,
+		Strict:      true,
+	}
+
+	var cfg myConfig
+	require.NoError(t, Read(&cfg, opts))
+
+	t.Setenv("TESTAPP_DATABSE_HOST", "localhost")
+	t.Setenv("TESTAPP_UNRELATED", "1")
+
+	err := Read(&cfg, opts)
+	require.ErrorIs(t, err, ErrUnknownVariables)
+
+	var unknownErr *UnknownVariablesError
+	require.ErrorAs(t, err, &unknownErr)
+	expected := []UnknownVariable{
+		{Name: "TESTAPP_DATABSE_HOST", Suggestion: "TESTAPP_DATABASE_HOST"},
+		{Name: "TESTAPP_UNRELATED"},
+	}
+	assert.Equal(t, expected, unknownErr.Variables)
+	assert.Equal(t, "unknown environment variables: TESTAPP_DATABSE_HOST (did you mean TESTAPP_DATABASE_HOST?), "+
+		"TESTAPP_UNRELATED", err.Error())
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("HOST", "HOST"))
+	assert.Equal(t, 1, editDistance("HOST", "HOSTS"))
+	assert.Equal(t, 1, editDistance("DATABSE", "DATABASE"))
+	assert.Equal(t, 4, editDistance("", "HOST"))
+	assert.Equal(t, 3, editDistance("kitten", "sitting"))
+}
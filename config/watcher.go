@@ -0,0 +1,259 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	envparser "github.com/caarlos0/env/v11"
+	"github.com/cornelk/gotokit/envfile"
+)
+
+// DefaultPollInterval is the interval that watched files are checked for changes
+// if no other interval is set.
+const DefaultPollInterval = 5 * time.Second
+
+// WatchOptions configures when a Watcher reloads the config.
+type WatchOptions struct {
+	// EnvFiles are read using envfile.Read before every read of the config, their
+	// variables override the ones of the environment. Missing files are ignored, a
+	// file with invalid syntax fails the reload. The files are also watched for changes.
+	EnvFiles []string
+	// Files are watched for changes and trigger a reload, for example secret files.
+	Files []string
+	// PollInterval is the interval that files are checked for changes, defaults
+	// to DefaultPollInterval.
+	PollInterval time.Duration
+	// Signals trigger a reload when received, defaults to SIGHUP.
+	Signals []os.Signal
+	// OnError is called when a reload triggered by Run fails.
+	OnError func(error)
+}
+
+// Watcher holds a config value that is reloaded on a signal or on file changes.
+// A reloaded config is validated before it replaces the current config, if the
+// config type implements a Validate() error function. If reading or validating
+// fails, the current config is kept.
+type Watcher[T any] struct {
+	opts      Options
+	watchOpts WatchOptions
+	files     []string // all watched files
+	states    string   // fingerprint of the watched files at the last check
+	value     atomic.Pointer[T]
+
+	mu          sync.Mutex // serializes reloads and protects the fields below
+	subscribers []func(previous, current T)
+	pending     []change[T] // changes that subscribers have not been notified about
+	notifying   bool        // whether a reload is delivering the pending changes
+}
+
+// change is a replacement of the config that subscribers are notified about.
+type change[T any] struct {
+	previous, current *T
+}
+
+// NewWatcher returns a new watcher for the config type T and reads the initial config.
+func NewWatcher[T any](opts Options, watchOpts WatchOptions) (*Watcher[T], error) {
+	if watchOpts.PollInterval <= 0 {
+		watchOpts.PollInterval = DefaultPollInterval
+	}
+	if len(watchOpts.Signals) == 0 {
+		watchOpts.Signals = []os.Signal{syscall.SIGHUP}
+	}
+
+	w := &Watcher[T]{
+		opts:      opts,
+		watchOpts: watchOpts,
+		files:     append(append([]string{}, watchOpts.EnvFiles...), watchOpts.Files...),
+	}
+	w.states = fileStates(w.files)
+
+	cfg, err := w.read(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	w.value.Store(cfg)
+	return w, nil
+}
+
+// Load returns the current config.
+func (w *Watcher[T]) Load() T {
+	return *w.value.Load()
+}
+
+// Subscribe registers a function that is called with the previous and current
+// config after every successful reload.
+func (w *Watcher[T]) Subscribe(fn func(previous, current T)) {
+	w.mu.Lock()
+	w.subscribers = append(w.subscribers, fn)
+	w.mu.Unlock()
+}
+
+// Reload reads and validates the config and replaces the current config with it.
+// The current config is kept if an error occurs.
+func (w *Watcher[T]) Reload() error {
+	return w.ReloadContext(context.Background())
+}
+
+// ReloadContext reloads the config like Reload, the context is passed to the
+// configured sources. Subscribers are notified in the order that the config was
+// replaced. If another reload is notifying the subscribers, that reload also
+// delivers the change of this reload.
+func (w *Watcher[T]) ReloadContext(ctx context.Context) error {
+	w.mu.Lock()
+
+	cfg, err := w.read(ctx)
+	if err != nil {
+		w.mu.Unlock()
+		return err
+	}
+
+	previous := w.value.Swap(cfg)
+	w.pending = append(w.pending, change[T]{previous: previous, current: cfg})
+	if w.notifying {
+		w.mu.Unlock()
+		return nil
+	}
+	w.notifying = true
+	defer func() {
+		w.notifying = false
+		w.mu.Unlock()
+	}()
+
+	// subscribers are called without holding the lock to allow them to subscribe or reload
+	for len(w.pending) > 0 {
+		c := w.pending[0]
+		w.pending = w.pending[1:]
+		subscribers := slices.Clone(w.subscribers)
+
+		w.mu.Unlock()
+		func() {
+			defer w.mu.Lock() // keeps the lock state consistent if a subscriber panics
+			for _, fn := range subscribers {
+				fn(*c.previous, *c.current)
+			}
+		}()
+	}
+	return nil
+}
+
+// Run reloads the config whenever one of the configured signals is received, one
+// of the watched files changes or one of the sources of the options reports a change.
+// It blocks until the context is done and must not be called multiple times concurrently.
+func (w *Watcher[T]) Run(ctx context.Context) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, w.watchOpts.Signals...)
+	defer signal.Stop(signals)
+
+	changes := make(chan struct{}, 1)
+	for _, source := range w.opts.Sources {
+		go func() {
+			_ = source.Watch(ctx, func() {
+				select {
+				case changes <- struct{}{}:
+				default: // a reload is already pending
+				}
+			})
+		}()
+	}
+
+	ticker := time.NewTicker(w.watchOpts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case <-signals:
+			w.reloadWithErrorHandler(ctx)
+
+		case <-changes:
+			w.reloadWithErrorHandler(ctx)
+
+		case <-ticker.C:
+			current := fileStates(w.files)
+			if current != w.states {
+				w.states = current
+				w.reloadWithErrorHandler(ctx)
+			}
+		}
+	}
+}
+
+func (w *Watcher[T]) reloadWithErrorHandler(ctx context.Context) {
+	if err := w.ReloadContext(ctx); err != nil && w.watchOpts.OnError != nil {
+		w.watchOpts.OnError(err)
+	}
+}
+
+func (w *Watcher[T]) read(ctx context.Context) (*T, error) {
+	opts := w.opts
+	if len(w.watchOpts.EnvFiles) > 0 {
+		variables, err := w.readEnvFiles()
+		if err != nil {
+			return nil, err
+		}
+		opts.Variables = variables
+	}
+
+	cfg := new(T)
+	if err := ReadContext(ctx, cfg, opts); err != nil {
+		return nil, err
+	}
+
+	if v, ok := any(cfg).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return nil, fmt.Errorf("validating config: %w", err)
+		}
+	}
+	return cfg, nil
+}
+
+// readEnvFiles returns the variables of the environment, or of the options if set, with
+// the variables of the existing env files applied. The process environment is not
+// changed, which makes variables that got removed from an env file disappear on reload.
+func (w *Watcher[T]) readEnvFiles() (map[string]string, error) {
+	var files []string
+	for _, file := range w.watchOpts.EnvFiles {
+		if _, err := os.Stat(file); err == nil {
+			files = append(files, file)
+		}
+	}
+
+	variables := maps.Clone(w.opts.Variables)
+	if variables == nil {
+		variables = envparser.ToMap(os.Environ())
+	}
+	if len(files) == 0 {
+		return variables, nil
+	}
+
+	values, err := envfile.Read(files...)
+	if err != nil {
+		return nil, fmt.Errorf("reading env files: %w", err)
+	}
+	maps.Copy(variables, values)
+	return variables, nil
+}
+
+// fileStates returns a fingerprint of the modification times and sizes of the given files.
+func fileStates(files []string) string {
+	var states string
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			states += file + ":missing;"
+			continue
+		}
+		states += fmt.Sprintf("%s:%d:%d;", file, info.ModTime().UnixNano(), info.Size())
+	}
+	return states
+}
@@ -0,0 +1,265 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type watchedConfig struct {
+	Limit int `env:"WATCHER_LIMIT"`
+}
+
+func (c *watchedConfig) Validate() error {
+	if c.Limit < 0 {
+		return errors.New("limit must not be negative")
+	}
+	return nil
+}
+
+func TestWatcherReload(t *testing.T) {
+	t.Setenv("WATCHER_LIMIT", "1")
+
+	w, err := NewWatcher[watchedConfig](Options{}, WatchOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, w.Load().Limit)
+
+	var previous, current watchedConfig
+	w.Subscribe(func(p, c watchedConfig) {
+		previous, current = p, c
+	})
+
+	t.Setenv("WATCHER_LIMIT", "2")
+	require.NoError(t, w.Reload())
+	assert.Equal(t, 2, w.Load().Limit)
+	assert.Equal(t, 1, previous.Limit)
+	assert.Equal(t, 2, current.Limit)
+
+	// invalid configs are not applied
+	t.Setenv("WATCHER_LIMIT", "-1")
+	require.Error(t, w.Reload())
+	assert.Equal(t, 2, w.Load().Limit)
+
+	t.Setenv("WATCHER_LIMIT", "invalid")
+	require.Error(t, w.Reload())
+	assert.Equal(t, 2, w.Load().Limit)
+}
+
+func TestWatcherRun(t *testing.T) {
+	t.Setenv("WATCHER_LIMIT", "")
+
+	file := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(file, []byte("WATCHER_LIMIT=1\n"), 0o644))
+
+	w, err := NewWatcher[watchedConfig](Options{}, WatchOptions{
+		EnvFiles:     []string{file},
+		PollInterval: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, w.Load().Limit)
+
+	changed := make(chan watchedConfig, 1)
+	w.Subscribe(func(_, current watchedConfig) {
+		changed <- current
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- w.Run(ctx)
+	}()
+
+	require.NoError(t, os.WriteFile(file, []byte("WATCHER_LIMIT=10\n"), 0o644))
+
+	select {
+	case cfg := <-changed:
+		assert.Equal(t, 10, cfg.Limit)
+	case <-time.After(5 * time.Second):
+		t.Fatal("config was not reloaded")
+	}
+	assert.Equal(t, 10, w.Load().Limit)
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestWatcherRunSource(t *testing.T) {
+	t.Setenv("WATCHER_LIMIT", "")
+	require.NoError(t, os.Unsetenv("WATCHER_LIMIT"))
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "WATCHER_LIMIT")
+	require.NoError(t, os.WriteFile(file, []byte("1"), 0o644))
+
+	source := NewDirectorySource(dir)
+	source.PollInterval = 10 * time.Millisecond
+
+	w, err := NewWatcher[watchedConfig](Options{Sources: []Source{source}}, WatchOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, w.Load().Limit)
+
+	changed := make(chan watchedConfig, 1)
+	w.Subscribe(func(_, current watchedConfig) {
+		changed <- current
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = w.Run(ctx)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, os.WriteFile(file, []byte("20"), 0o644))
+
+	select {
+	case cfg := <-changed:
+		assert.Equal(t, 20, cfg.Limit)
+	case <-time.After(5 * time.Second):
+		t.Fatal("config was not reloaded")
+	}
+}
+
+func TestWatcherReloadEnvFiles(t *testing.T) {
+	t.Setenv("WATCHER_LIMIT", "")
+	require.NoError(t, os.Unsetenv("WATCHER_LIMIT"))
+
+	file := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(file, []byte("WATCHER_LIMIT=1\n"), 0o644))
+
+	w, err := NewWatcher[watchedConfig](Options{}, WatchOptions{EnvFiles: []string{file}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, w.Load().Limit)
+	_, ok := os.LookupEnv("WATCHER_LIMIT")
+	assert.False(t, ok, "process environment must not be changed")
+
+	// invalid syntax fails the reload and keeps the current config
+	require.NoError(t, os.WriteFile(file, []byte("WATCHER_LIMIT=\"2\n"), 0o644))
+	require.Error(t, w.Reload())
+	assert.Equal(t, 1, w.Load().Limit)
+
+	// removed variables are removed from the config
+	require.NoError(t, os.WriteFile(file, []byte("# empty\n"), 0o644))
+	require.NoError(t, w.Reload())
+	assert.Equal(t, 0, w.Load().Limit)
+}
+
+func TestWatcherSubscriberReentrant(t *testing.T) {
+	t.Setenv("WATCHER_LIMIT", "1")
+
+	w, err := NewWatcher[watchedConfig](Options{}, WatchOptions{})
+	require.NoError(t, err)
+
+	var calls int
+	w.Subscribe(func(_, _ watchedConfig) {
+		calls++
+		if calls == 1 {
+			w.Subscribe(func(_, _ watchedConfig) {})
+			assert.NoError(t, w.Reload())
+		}
+	})
+
+	require.NoError(t, w.Reload())
+	assert.Equal(t, 2, calls)
+}
+
+// contextSource is a source that fails when its context is done.
+type contextSource struct{}
+
+func (contextSource) Values(ctx context.Context) (map[string]string, error) {
+	return map[string]string{}, ctx.Err()
+}
+
+func (contextSource) Watch(ctx context.Context, _ func()) error {
+	<-ctx.Done()
+	return nil
+}
+
+func TestWatcherReloadContext(t *testing.T) {
+	w, err := NewWatcher[watchedConfig](Options{Sources: []Source{contextSource{}}}, WatchOptions{})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, w.ReloadContext(ctx), context.Canceled)
+	require.NoError(t, w.Reload())
+}
+
+func TestWatcherNotificationOrder(t *testing.T) {
+	t.Setenv("WATCHER_LIMIT", "0")
+
+	w, err := NewWatcher[watchedConfig](Options{}, WatchOptions{})
+	require.NoError(t, err)
+
+	// a reload during the notification of another reload is delivered afterwards
+	var changes [][2]int
+	w.Subscribe(func(previous, current watchedConfig) {
+		changes = append(changes, [2]int{previous.Limit, current.Limit})
+		if current.Limit == 1 {
+			t.Setenv("WATCHER_LIMIT", "2")
+			assert.NoError(t, w.Reload())
+			assert.Len(t, changes, 1, "nested change must not be delivered before the current one")
+		}
+	})
+
+	t.Setenv("WATCHER_LIMIT", "1")
+	require.NoError(t, w.Reload())
+	assert.Equal(t, [][2]int{{0, 1}, {1, 2}}, changes)
+	assert.Equal(t, 2, w.Load().Limit)
+}
+
+// counterSource is a source that returns an increasing limit on every read.
+type counterSource struct {
+	count atomic.Int64
+}
+
+func (s *counterSource) Values(_ context.Context) (map[string]string, error) {
+	return map[string]string{"WATCHER_LIMIT": strconv.FormatInt(s.count.Add(1), 10)}, nil
+}
+
+func (s *counterSource) Watch(ctx context.Context, _ func()) error {
+	<-ctx.Done()
+	return nil
+}
+
+func TestWatcherConcurrentReloads(t *testing.T) {
+	t.Setenv("WATCHER_LIMIT", "")
+	require.NoError(t, os.Unsetenv("WATCHER_LIMIT"))
+
+	w, err := NewWatcher[watchedConfig](Options{Sources: []Source{&counterSource{}}}, WatchOptions{})
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var last *watchedConfig
+	w.Subscribe(func(previous, current watchedConfig) {
+		mu.Lock()
+		defer mu.Unlock()
+		if last != nil {
+			assert.Equal(t, *last, previous, "notifications must follow the swap order")
+		}
+		last = &current
+	})
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, w.Reload())
+		}()
+	}
+	wg.Wait()
+
+	require.NotNil(t, last)
+	assert.Equal(t, w.Load(), *last)
+	assert.Equal(t, 21, last.Limit)
+}
@@ -5,8 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"net"
+	"net/url"
+	"slices"
 	"strconv"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jackc/pgx/v5/tracelog"
 )
 
@@ -21,13 +26,98 @@ type Config struct {
 	Host     string `env:"HOST"`
 	Port     string `env:"PORT"`
 	User     string `env:"USER"`
-	Password string `env:"PASSWORD"`
+	Password string `env:"PASSWORD" secret:"true"`
 	Database string `env:"DATABASE"`
 	Driver   string `env:"DRIVER"`
 
+	SSLMode          string        `env:"SSLMODE"`           // disable, allow, prefer, require, verify-ca or verify-full
+	SSLRootCert      string        `env:"SSLROOTCERT"`       // path of the root certificate file
+	ApplicationName  string        `env:"APPLICATION_NAME"`  // name shown in pg_stat_activity
+	SearchPath       string        `env:"SEARCH_PATH"`       // comma separated schema search path
+	ConnectTimeout   time.Duration `env:"CONNECT_TIMEOUT"`   // rounded up to full seconds
+	StatementTimeout time.Duration `env:"STATEMENT_TIMEOUT"` // rounded to milliseconds
+
+	// Pool settings, zero values use the pgxpool defaults.
+	MaxConns          int32         `env:"MAX_CONNS"`           // maximum size of the pool
+	MinConns          int32         `env:"MIN_CONNS"`           // minimum size of the pool
+	MaxConnLifetime   time.Duration `env:"MAX_CONN_LIFETIME"`   // duration after which a connection is closed
+	MaxConnIdleTime   time.Duration `env:"MAX_CONN_IDLE_TIME"`  // duration after which an idle connection is closed
+	HealthCheckPeriod time.Duration `env:"HEALTH_CHECK_PERIOD"` // interval of health checks of idle connections
+	PingOnConnect     bool          `env:"PING_ON_CONNECT"`     // checks the connection when creating the pool
+
 	Logger LoggerContract
 }
 
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// DSN returns the connection URL for the config. All values are escaped.
+func (cfg Config) DSN() string {
+	return cfg.url().String()
+}
+
+// String returns the connection URL for the config with a redacted password.
+func (cfg Config) String() string {
+	return cfg.url().Redacted()
+}
+
+// url returns the connection URL for the config.
+func (cfg Config) url() *url.URL {
+	u := &url.URL{
+		Scheme:  "postgres",
+		Host:    net.JoinHostPort(cfg.Host, cfg.Port),
+		Path:    "/" + cfg.Database,
+		RawPath: "/" + url.PathEscape(cfg.Database),
+	}
+	if cfg.Password == "" {
+		u.User = url.User(cfg.User)
+	} else {
+		u.User = url.UserPassword(cfg.User, cfg.Password)
+	}
+
+	query := url.Values{}
+	if cfg.SSLMode != "" {
+		query.Set("sslmode", cfg.SSLMode)
+	}
+	if cfg.SSLRootCert != "" {
+		query.Set("sslrootcert", cfg.SSLRootCert)
+	}
+	if cfg.ApplicationName != "" {
+		query.Set("application_name", cfg.ApplicationName)
+	}
+	if cfg.SearchPath != "" {
+		query.Set("search_path", cfg.SearchPath)
+	}
+	if cfg.ConnectTimeout > 0 {
+		seconds := (cfg.ConnectTimeout + time.Second - 1) / time.Second
+		query.Set("connect_timeout", strconv.FormatInt(int64(seconds), 10))
+	}
+	if cfg.StatementTimeout > 0 {
+		query.Set("statement_timeout", strconv.FormatInt(cfg.StatementTimeout.Milliseconds(), 10))
+	}
+	u.RawQuery = query.Encode()
+
+	return u
+}
+
+// applyPoolConfig sets the pool settings of the config in the pgxpool config.
+func (cfg *Config) applyPoolConfig(poolConfig *pgxpool.Config) {
+	if cfg.MaxConns > 0 {
+		poolConfig.MaxConns = cfg.MaxConns
+	}
+	if cfg.MinConns > 0 {
+		poolConfig.MinConns = cfg.MinConns
+	}
+	if cfg.MaxConnLifetime > 0 {
+		poolConfig.MaxConnLifetime = cfg.MaxConnLifetime
+	}
+	if cfg.MaxConnIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
+	}
+	if cfg.HealthCheckPeriod > 0 {
+		poolConfig.HealthCheckPeriod = cfg.HealthCheckPeriod
+	}
+}
+
 // Validate checks that all mandatory configuration values are set.
 func (cfg *Config) Validate() error {
 	var errs []error
@@ -47,6 +137,30 @@ func (cfg *Config) Validate() error {
 	if cfg.User == "" {
 		errs = append(errs, errors.New("user is not set"))
 	}
+	if cfg.SSLMode != "" && !slices.Contains(sslModes, cfg.SSLMode) {
+		errs = append(errs, fmt.Errorf("invalid sslmode '%s'", cfg.SSLMode))
+	}
+	if cfg.ConnectTimeout < 0 || cfg.StatementTimeout < 0 {
+		errs = append(errs, errors.New("negative timeout set"))
+	}
+	errs = append(errs, cfg.validatePool()...)
 
 	return errors.Join(errs...)
 }
+
+// validatePool checks the pool settings.
+func (cfg *Config) validatePool() []error {
+	var errs []error
+
+	if cfg.MaxConns < 0 || cfg.MinConns < 0 {
+		errs = append(errs, errors.New("negative connection count set"))
+	}
+	if cfg.MaxConns > 0 && cfg.MinConns > cfg.MaxConns {
+		errs = append(errs, errors.New("minimum connections exceed maximum connections"))
+	}
+	if cfg.MaxConnLifetime < 0 || cfg.MaxConnIdleTime < 0 || cfg.HealthCheckPeriod < 0 {
+		errs = append(errs, errors.New("negative pool duration set"))
+	}
+
+	return errs
+}
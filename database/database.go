@@ -6,7 +6,6 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"net"
 
 	"github.com/georgysavva/scany/v2/pgxscan"
 	"github.com/jackc/pgx/v5"
@@ -47,6 +46,7 @@ var (
 	// ErrNoRows occurs when rows are expected but none are returned.
 	ErrNoRows = pgx.ErrNoRows
 	// ErrRowAlreadyExists occurs when a row already exists when trying to insert.
+	// Errors of unique violations returned by Select and Get match it.
 	ErrRowAlreadyExists = errors.New("row already exists")
 	// ErrTooManyRows occurs when more rows than expected are returned.
 	ErrTooManyRows = pgx.ErrTooManyRows
@@ -58,9 +58,7 @@ func New(ctx context.Context, cfg Config) (*Connection, error) {
 		return nil, fmt.Errorf("validating config: %w", err)
 	}
 
-	hostPort := net.JoinHostPort(cfg.Host, cfg.Port)
-	url := fmt.Sprintf("postgres://%s:%s@%s/%s", cfg.User, cfg.Password, hostPort, cfg.Database)
-	connConfig, err := pgx.ParseConfig(url) // nolint: contextcheck
+	connConfig, err := pgx.ParseConfig(cfg.DSN()) // nolint: contextcheck
 	if err != nil {
 		return nil, fmt.Errorf("parsing config: %w", err)
 	}
@@ -98,9 +96,7 @@ func NewStdlib(ctx context.Context, cfg Config) (*sql.DB, error) {
 		return nil, fmt.Errorf("validating config: %w", err)
 	}
 
-	hostPort := net.JoinHostPort(cfg.Host, cfg.Port)
-	url := fmt.Sprintf("postgres://%s:%s@%s/%s", cfg.User, cfg.Password, hostPort, cfg.Database)
-	db, err := sql.Open("pgx", url)
+	db, err := sql.Open("pgx", cfg.DSN())
 	if err != nil {
 		return nil, fmt.Errorf("creating database connection: %w", err)
 	}
@@ -113,14 +109,15 @@ func NewStdlib(ctx context.Context, cfg Config) (*sql.DB, error) {
 }
 
 // NewPool establishes a connection pool with a PostgreSQL database.
+// Connections are established on first use of the pool. If PingOnConnect is set in the
+// config, the connection is checked by a ping before returning and an unreachable
+// database returns an error.
 func NewPool(ctx context.Context, cfg Config) (*Pool, error) {
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("validating config: %w", err)
 	}
 
-	hostPort := net.JoinHostPort(cfg.Host, cfg.Port)
-	url := fmt.Sprintf("postgres://%s:%s@%s/%s", cfg.User, cfg.Password, hostPort, cfg.Database)
-	connConfig, err := pgxpool.ParseConfig(url) // nolint: contextcheck
+	connConfig, err := pgxpool.ParseConfig(cfg.DSN()) // nolint: contextcheck
 	if err != nil {
 		return nil, fmt.Errorf("parsing config: %w", err)
 	}
@@ -131,10 +128,18 @@ func NewPool(ctx context.Context, cfg Config) (*Pool, error) {
 		}
 	}
 
+	cfg.applyPoolConfig(connConfig)
+
 	pool, err := pgxpool.NewWithConfig(ctx, connConfig)
 	if err != nil {
 		return nil, fmt.Errorf("connecting to database: %w", err)
 	}
+	if cfg.PingOnConnect {
+		if err := pool.Ping(ctx); err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("pinging database: %w", err)
+		}
+	}
 
 	scan, err := pgxscan.NewDBScanAPI()
 	if err != nil {
@@ -152,6 +157,30 @@ func NewPool(ctx context.Context, cfg Config) (*Pool, error) {
 	return conn, nil
 }
 
+// Select executes the query and scans all rows into dst, which must be a pointer to a
+// slice. Unique violations match ErrRowAlreadyExists.
+func (c *Connection) Select(ctx context.Context, db Querier, dst any, query string, args ...any) error {
+	return wrapQueryError(c.API.Select(ctx, db, dst, query, args...))
+}
+
+// Get executes the query and scans the single result row into dst. It returns ErrNoRows
+// if the query returns no rows, unique violations match ErrRowAlreadyExists.
+func (c *Connection) Get(ctx context.Context, db Querier, dst any, query string, args ...any) error {
+	return wrapQueryError(c.API.Get(ctx, db, dst, query, args...))
+}
+
+// Select executes the query and scans all rows into dst, which must be a pointer to a
+// slice. Unique violations match ErrRowAlreadyExists.
+func (p *Pool) Select(ctx context.Context, db Querier, dst any, query string, args ...any) error {
+	return wrapQueryError(p.API.Select(ctx, db, dst, query, args...))
+}
+
+// Get executes the query and scans the single result row into dst. It returns ErrNoRows
+// if the query returns no rows, unique violations match ErrRowAlreadyExists.
+func (p *Pool) Get(ctx context.Context, db Querier, dst any, query string, args ...any) error {
+	return wrapQueryError(p.API.Get(ctx, db, dst, query, args...))
+}
+
 // Close closes all connections in the pool and rejects future Acquire calls.
 // Blocks until all connections are returned to pool and closed.
 // Implement the same Close function signature to allow Pool and Connection to have the
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -35,3 +36,63 @@ func TestNewStdlib(t *testing.T) {
 	require.Error(t, err)
 	assert.Nil(t, db)
 }
+
+func TestNewPool(t *testing.T) {
+	cfg := Config{
+		Host:              "127.0.0.1",
+		Port:              "1", // no server is listening
+		User:              "default",
+		Database:          "test",
+		MaxConns:          8,
+		MinConns:          0,
+		MaxConnLifetime:   time.Hour,
+		MaxConnIdleTime:   time.Minute,
+		HealthCheckPeriod: 10 * time.Second,
+		ConnectTimeout:    time.Second,
+	}
+
+	ctx := context.Background()
+	cfg.PingOnConnect = true
+	pool, err := NewPool(ctx, cfg)
+	require.Error(t, err)
+	assert.Nil(t, pool)
+
+	// connections are established on first use by default
+	cfg.PingOnConnect = false
+	pool, err = NewPool(ctx, cfg)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, pool.Close(ctx))
+	}()
+
+	poolConfig := pool.Config()
+	assert.Equal(t, int32(8), poolConfig.MaxConns)
+	assert.Equal(t, time.Hour, poolConfig.MaxConnLifetime)
+	assert.Equal(t, time.Minute, poolConfig.MaxConnIdleTime)
+	assert.Equal(t, 10*time.Second, poolConfig.HealthCheckPeriod)
+
+	stat := pool.Stat()
+	assert.Equal(t, int32(8), stat.MaxConns)
+	assert.Len(t, stat.Fields(), 12)
+
+	reportCtx, cancel := context.WithCancel(ctx)
+	reported := make(chan PoolStat, 1)
+	go pool.ReportStat(reportCtx, time.Millisecond, func(stat PoolStat) {
+		select {
+		case reported <- stat:
+		default:
+		}
+	})
+	select {
+	case stat := <-reported:
+		assert.Equal(t, int32(8), stat.MaxConns)
+	case <-time.After(5 * time.Second):
+		t.Fatal("stats were not reported")
+	}
+	cancel()
+
+	// an invalid interval falls back to the default
+	reportCtx, cancel = context.WithCancel(ctx)
+	cancel()
+	pool.ReportStat(reportCtx, 0, func(PoolStat) {})
+}
@@ -0,0 +1,143 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// SQLSTATE codes of classified PostgreSQL errors.
+const (
+	sqlStateUniqueViolation      = "23505"
+	sqlStateForeignKeyViolation  = "23503"
+	sqlStateCheckViolation       = "23514"
+	sqlStateNotNullViolation     = "23502"
+	sqlStateQueryCanceled        = "57014"
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+	sqlStateClassConnection      = "08"
+)
+
+// ErrorDetails contains the details of a PostgreSQL error.
+type ErrorDetails struct {
+	Code       string // SQLSTATE code
+	Message    string
+	Detail     string
+	Schema     string
+	Table      string
+	Column     string
+	Constraint string
+}
+
+// IsUniqueViolation returns whether the error is a unique constraint violation and
+// the details of the error, like the name of the violated constraint.
+func IsUniqueViolation(err error) (ErrorDetails, bool) {
+	return hasCode(err, sqlStateUniqueViolation)
+}
+
+// IsForeignKeyViolation returns whether the error is a foreign key constraint violation
+// and the details of the error.
+func IsForeignKeyViolation(err error) (ErrorDetails, bool) {
+	return hasCode(err, sqlStateForeignKeyViolation)
+}
+
+// IsCheckViolation returns whether the error is a check constraint violation and the
+// details of the error.
+func IsCheckViolation(err error) (ErrorDetails, bool) {
+	return hasCode(err, sqlStateCheckViolation)
+}
+
+// IsNotNullViolation returns whether the error is a not null constraint violation and
+// the details of the error, like the name of the column.
+func IsNotNullViolation(err error) (ErrorDetails, bool) {
+	return hasCode(err, sqlStateNotNullViolation)
+}
+
+// IsSerializationFailure returns whether the error is a serialization failure of a
+// transaction and the details of the error.
+func IsSerializationFailure(err error) (ErrorDetails, bool) {
+	return hasCode(err, sqlStateSerializationFailure)
+}
+
+// IsDeadlock returns whether the error is a detected deadlock and the details of the error.
+func IsDeadlock(err error) (ErrorDetails, bool) {
+	return hasCode(err, sqlStateDeadlockDetected)
+}
+
+// IsQueryCanceled returns whether the query was canceled, either by the server because
+// of a statement timeout or because the context of the query was done.
+func IsQueryCanceled(err error) (ErrorDetails, bool) {
+	if details, ok := hasCode(err, sqlStateQueryCanceled); ok {
+		return details, true
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return ErrorDetails{Message: err.Error()}, true
+	}
+	return ErrorDetails{}, false
+}
+
+// IsConnectionError returns whether the error is caused by a failed or broken connection
+// to the database server. This includes connection errors reported by the server,
+// failed connection attempts and connections that were closed or failed during a query.
+func IsConnectionError(err error) (ErrorDetails, bool) {
+	if details, ok := pgErrorDetails(err); ok {
+		if !strings.HasPrefix(details.Code, sqlStateClassConnection) {
+			return ErrorDetails{}, false
+		}
+		return details, true
+	}
+
+	// timeouts and canceled contexts are reported by IsQueryCanceled
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return ErrorDetails{}, false
+	}
+
+	var connectErr *pgconn.ConnectError
+	var opErr *net.OpError
+	if errors.As(err, &connectErr) || errors.As(err, &opErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrorDetails{Message: err.Error()}, true
+	}
+	return ErrorDetails{}, false
+}
+
+// hasCode returns whether the error is a PostgreSQL error with the given code and
+// the details of the error.
+func hasCode(err error, code string) (ErrorDetails, bool) {
+	details, ok := pgErrorDetails(err)
+	if !ok || details.Code != code {
+		return ErrorDetails{}, false
+	}
+	return details, true
+}
+
+// pgErrorDetails returns the details of a PostgreSQL error.
+func pgErrorDetails(err error) (ErrorDetails, bool) {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return ErrorDetails{}, false
+	}
+
+	return ErrorDetails{
+		Code:       pgErr.Code,
+		Message:    pgErr.Message,
+		Detail:     pgErr.Detail,
+		Schema:     pgErr.SchemaName,
+		Table:      pgErr.TableName,
+		Column:     pgErr.ColumnName,
+		Constraint: pgErr.ConstraintName,
+	}, true
+}
+
+// wrapQueryError wraps unique violations so that they match ErrRowAlreadyExists,
+// the PostgreSQL error stays accessible.
+func wrapQueryError(err error) error {
+	if _, ok := IsUniqueViolation(err); ok {
+		return fmt.Errorf("%w: %w", ErrRowAlreadyExists, err)
+	}
+	return err
+}
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorClassification(t *testing.T) {
+	pgErr := &pgconn.PgError{
+		Code:           "23505",
+		Message:        "duplicate key value violates unique constraint",
+		Detail:         "Key (email)=(a@b.c) already exists.",
+		SchemaName:     "public",
+		TableName:      "users",
+		ConstraintName: "users_email_key",
+	}
+	err := fmt.Errorf("inserting user: %w", pgErr)
+
+	details, ok := IsUniqueViolation(err)
+	require.True(t, ok)
+	assert.Equal(t, ErrorDetails{
+		Code:       "23505",
+		Message:    pgErr.Message,
+		Detail:     pgErr.Detail,
+		Schema:     "public",
+		Table:      "users",
+		Constraint: "users_email_key",
+	}, details)
+
+	_, ok = IsForeignKeyViolation(err)
+	assert.False(t, ok)
+	_, ok = IsUniqueViolation(errors.New("other"))
+	assert.False(t, ok)
+
+	classifiers := map[string]func(error) (ErrorDetails, bool){
+		"23503": IsForeignKeyViolation,
+		"23514": IsCheckViolation,
+		"23502": IsNotNullViolation,
+		"40001": IsSerializationFailure,
+		"40P01": IsDeadlock,
+		"57014": IsQueryCanceled,
+		"08006": IsConnectionError,
+	}
+	for code, classify := range classifiers {
+		details, ok := classify(&pgconn.PgError{Code: code, ColumnName: "column"})
+		assert.True(t, ok, code)
+		assert.Equal(t, "column", details.Column, code)
+	}
+
+	_, ok = IsQueryCanceled(fmt.Errorf("query: %w", context.DeadlineExceeded))
+	assert.True(t, ok)
+	details, ok = IsConnectionError(&pgconn.PgError{Code: "23505", ColumnName: "column"})
+	assert.False(t, ok)
+	assert.Empty(t, details)
+
+	// connections that break during a query
+	_, ok = IsConnectionError(fmt.Errorf("query: %w", io.ErrUnexpectedEOF))
+	assert.True(t, ok)
+	_, ok = IsConnectionError(fmt.Errorf("query: %w", &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}))
+	assert.True(t, ok)
+	_, ok = IsConnectionError(ErrNoRows)
+	assert.False(t, ok)
+
+	// timeouts are canceled queries, not connection errors
+	_, ok = IsConnectionError(fmt.Errorf("query: %w", context.DeadlineExceeded))
+	assert.False(t, ok)
+	_, ok = IsConnectionError(fmt.Errorf("query: %w", context.Canceled))
+	assert.False(t, ok)
+}
+
+func TestWrapQueryError(t *testing.T) {
+	pgErr := &pgconn.PgError{Code: "23505", ConstraintName: "users_pkey"}
+	err := wrapQueryError(pgErr)
+	require.ErrorIs(t, err, ErrRowAlreadyExists)
+
+	details, ok := IsUniqueViolation(err)
+	require.True(t, ok)
+	assert.Equal(t, "users_pkey", details.Constraint)
+
+	assert.Equal(t, ErrNoRows, wrapQueryError(ErrNoRows))
+	assert.NoError(t, wrapQueryError(nil))
+}
@@ -6,6 +6,7 @@ import (
 	"io/fs"
 	"net/http"
 
+	"github.com/cornelk/gotokit/env"
 	migrate "github.com/rubenv/sql-migrate"
 )
 
@@ -24,6 +25,20 @@ func Migrate(db *sql.DB, migrations fs.FS, direction MigrationDirection) (int, e
 	return MigrateMax(db, migrations, direction, 0)
 }
 
+// MigrateForEnv migrates the given database like Migrate, but refuses to migrate
+// down in production-like or unknown environments by returning an error wrapping
+// env.ErrNotAllowed.
+func MigrateForEnv(environment env.Environment, db *sql.DB, migrations fs.FS,
+	direction MigrationDirection) (int, error) {
+
+	if direction == DownMigration {
+		if err := environment.GuardDestructive("down migration"); err != nil {
+			return 0, fmt.Errorf("checking environment: %w", err)
+		}
+	}
+	return Migrate(db, migrations, direction)
+}
+
 // MigrateMax migrates the given database with the embedded migrations and direction,
 // will apply at most `max` migrations, pass 0 for no limit.
 func MigrateMax(db *sql.DB, migrations fs.FS, direction MigrationDirection, maxAmount int) (int, error) {
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"testing/fstest"
 
+	"github.com/cornelk/gotokit/env"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	_ "modernc.org/sqlite" // register driver
@@ -49,3 +50,32 @@ DROP TABLE IF EXISTS test;
 	_, err = Migrate(db, migrationFs, UpMigration)
 	require.Error(t, err)
 }
+
+func TestMigrateForEnv(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, db.Close())
+	}()
+
+	var migrationFs = fstest.MapFS{
+		"1234567890_init.sql": {
+			Data: []byte(`
+-- +migrate Up
+CREATE TABLE test (id INTEGER PRIMARY KEY NOT NULL);
+
+-- +migrate Down
+DROP TABLE IF EXISTS test;
+`),
+		},
+	}
+
+	applied, err := MigrateForEnv(env.Production, db, migrationFs, UpMigration)
+	require.NoError(t, err)
+	assert.Equal(t, 1, applied)
+
+	for _, environment := range []env.Environment{env.Staging, env.Production, "", "prodd"} {
+		_, err = MigrateForEnv(environment, db, migrationFs, DownMigration)
+		require.ErrorIs(t, err, env.ErrNotAllowed)
+	}
+}
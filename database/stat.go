@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/cornelk/gotokit/log"
+)
+
+// DefaultStatInterval is the interval of ReportStat if no valid interval is set.
+const DefaultStatInterval = 15 * time.Second
+
+// PoolStat contains the statistics of a connection pool.
+type PoolStat struct {
+	AcquireCount            int64
+	AcquireDuration         time.Duration
+	AcquiredConns           int32
+	CanceledAcquireCount    int64
+	ConstructingConns       int32
+	EmptyAcquireCount       int64
+	IdleConns               int32
+	MaxConns                int32
+	TotalConns              int32
+	NewConnsCount           int64
+	MaxLifetimeDestroyCount int64
+	MaxIdleDestroyCount     int64
+}
+
+// Fields returns the statistics as log fields.
+func (s PoolStat) Fields() []log.Field {
+	return []log.Field{
+		log.Int64("acquire_count", s.AcquireCount),
+		log.Duration("acquire_duration", s.AcquireDuration),
+		log.Int32("acquired_conns", s.AcquiredConns),
+		log.Int64("canceled_acquire_count", s.CanceledAcquireCount),
+		log.Int32("constructing_conns", s.ConstructingConns),
+		log.Int64("empty_acquire_count", s.EmptyAcquireCount),
+		log.Int32("idle_conns", s.IdleConns),
+		log.Int32("max_conns", s.MaxConns),
+		log.Int32("total_conns", s.TotalConns),
+		log.Int64("new_conns_count", s.NewConnsCount),
+		log.Int64("max_lifetime_destroy_count", s.MaxLifetimeDestroyCount),
+		log.Int64("max_idle_destroy_count", s.MaxIdleDestroyCount),
+	}
+}
+
+// Stat returns the current statistics of the pool. The statistics of the embedded
+// pgxpool.Pool are available using p.Pool.Stat().
+func (p *Pool) Stat() PoolStat {
+	stat := p.Pool.Stat()
+	return PoolStat{
+		AcquireCount:            stat.AcquireCount(),
+		AcquireDuration:         stat.AcquireDuration(),
+		AcquiredConns:           stat.AcquiredConns(),
+		CanceledAcquireCount:    stat.CanceledAcquireCount(),
+		ConstructingConns:       stat.ConstructingConns(),
+		EmptyAcquireCount:       stat.EmptyAcquireCount(),
+		IdleConns:               stat.IdleConns(),
+		MaxConns:                stat.MaxConns(),
+		TotalConns:              stat.TotalConns(),
+		NewConnsCount:           stat.NewConnsCount(),
+		MaxLifetimeDestroyCount: stat.MaxLifetimeDestroyCount(),
+		MaxIdleDestroyCount:     stat.MaxIdleDestroyCount(),
+	}
+}
+
+// ReportStat calls the given function with the statistics of the pool in the given
+// interval, for example to export them as metrics. The interval defaults to
+// DefaultStatInterval if it is not positive. It blocks until the context is done.
+func (p *Pool) ReportStat(ctx context.Context, interval time.Duration, report func(PoolStat)) {
+	if interval <= 0 {
+		interval = DefaultStatInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report(p.Stat())
+		}
+	}
+}
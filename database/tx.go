@@ -0,0 +1,165 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+)
+
+// Default retry settings of transactions.
+const (
+	DefaultTxMaxRetries = 3
+	DefaultTxRetryDelay = 20 * time.Millisecond
+)
+
+// IsoLevel is the transaction isolation level.
+type IsoLevel = pgx.TxIsoLevel
+
+// Transaction isolation levels.
+const (
+	Serializable    = pgx.Serializable
+	RepeatableRead  = pgx.RepeatableRead
+	ReadCommitted   = pgx.ReadCommitted
+	ReadUncommitted = pgx.ReadUncommitted
+)
+
+// TxOptions defines the options of a transaction.
+type TxOptions struct {
+	IsoLevel IsoLevel // isolation level, defaults to the database default
+	ReadOnly bool     // starts a read-only transaction
+
+	// MaxRetries is the number of retries on serialization failures and deadlocks,
+	// defaults to DefaultTxMaxRetries. A negative value disables retries.
+	MaxRetries int
+	// RetryDelay is the delay before the first retry, it doubles for every further
+	// retry. Defaults to DefaultTxRetryDelay.
+	RetryDelay time.Duration
+}
+
+// Tx defines a database transaction. It exposes the exported functions of pgx.Tx and
+// the scany functions Select and Get that query using the transaction.
+type Tx struct {
+	pgx.Tx
+
+	api *pgxscan.API
+}
+
+// Select executes the query in the transaction and scans all rows into dst, which
+// must be a pointer to a slice. Unique violations match ErrRowAlreadyExists.
+func (tx Tx) Select(ctx context.Context, dst any, query string, args ...any) error {
+	return wrapQueryError(tx.api.Select(ctx, tx.Tx, dst, query, args...))
+}
+
+// Get executes the query in the transaction and scans the single result row into dst.
+// It returns ErrNoRows if the query returns no rows, unique violations match
+// ErrRowAlreadyExists.
+func (tx Tx) Get(ctx context.Context, dst any, query string, args ...any) error {
+	return wrapQueryError(tx.api.Get(ctx, tx.Tx, dst, query, args...))
+}
+
+// WithTx runs the given function in a nested transaction that uses a savepoint. If the
+// function returns an error or panics, only the changes of the nested transaction are
+// rolled back.
+func (tx Tx) WithTx(ctx context.Context, fn func(tx Tx) error) error {
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("creating savepoint: %w", err)
+	}
+	return runTx(ctx, Tx{Tx: savepoint, api: tx.api}, fn)
+}
+
+// WithTx runs the given function in a transaction. The transaction is committed if the
+// function returns nil and rolled back if it returns an error or panics. On serialization
+// failures and deadlocks the whole function is retried with an exponential backoff, it
+// must therefore not have side effects outside of the transaction.
+func (p *Pool) WithTx(ctx context.Context, opts TxOptions, fn func(tx Tx) error) error {
+	begin := func(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
+		return p.BeginTx(ctx, txOptions)
+	}
+	return withTx(ctx, opts, begin, p.API, fn)
+}
+
+// withTx runs the function in transactions that are started using the begin function
+// until it succeeds or fails with an error that is not retryable.
+func withTx(ctx context.Context, opts TxOptions,
+	begin func(context.Context, pgx.TxOptions) (pgx.Tx, error), api *pgxscan.API, fn func(tx Tx) error) error {
+
+	maxRetries := opts.MaxRetries
+	switch {
+	case maxRetries == 0:
+		maxRetries = DefaultTxMaxRetries
+	case maxRetries < 0:
+		maxRetries = 0
+	}
+	delay := opts.RetryDelay
+	if delay <= 0 {
+		delay = DefaultTxRetryDelay
+	}
+
+	txOptions := pgx.TxOptions{
+		IsoLevel: opts.IsoLevel,
+	}
+	if opts.ReadOnly {
+		txOptions.AccessMode = pgx.ReadOnly
+	}
+
+	for attempt := 0; ; attempt++ {
+		pgxTx, err := begin(ctx, txOptions)
+		if err != nil {
+			return fmt.Errorf("beginning transaction: %w", err)
+		}
+
+		err = runTx(ctx, Tx{Tx: pgxTx, api: api}, fn)
+		if err == nil || attempt >= maxRetries || !isRetryable(err) {
+			return err
+		}
+
+		timer := time.NewTimer(delay << attempt)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, fmt.Errorf("waiting for transaction retry: %w", ctx.Err()))
+		case <-timer.C:
+		}
+	}
+}
+
+// runTx calls the function and commits the transaction if it returns nil. The transaction
+// is rolled back if the function returns an error or panics.
+func runTx(ctx context.Context, tx Tx, fn func(tx Tx) error) error {
+	// roll back even if the context got canceled
+	rollbackCtx := context.WithoutCancel(ctx)
+
+	defer func() {
+		if r := recover(); r != nil {
+			_ = tx.Rollback(rollbackCtx)
+			panic(r)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		if rollbackErr := tx.Rollback(rollbackCtx); rollbackErr != nil && !errors.Is(rollbackErr, pgx.ErrTxClosed) {
+			return errors.Join(err, fmt.Errorf("rolling back transaction: %w", rollbackErr))
+		}
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
+
+// isRetryable returns whether the error is a serialization failure or deadlock that
+// can be resolved by retrying the transaction.
+func isRetryable(err error) bool {
+	if _, ok := IsSerializationFailure(err); ok {
+		return true
+	}
+	_, ok := IsDeadlock(err)
+	return ok
+}
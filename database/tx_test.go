@@ -0,0 +1,148 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTx records the calls of a transaction, unused functions of pgx.Tx panic.
+type fakeTx struct {
+	pgx.Tx
+
+	committed  bool
+	rolledBack bool
+	commitErr  error
+	savepoints []*fakeTx
+}
+
+func (tx *fakeTx) Begin(_ context.Context) (pgx.Tx, error) {
+	savepoint := &fakeTx{}
+	tx.savepoints = append(tx.savepoints, savepoint)
+	return savepoint, nil
+}
+
+func (tx *fakeTx) Commit(_ context.Context) error {
+	tx.committed = true
+	return tx.commitErr
+}
+
+func (tx *fakeTx) Rollback(_ context.Context) error {
+	tx.rolledBack = true
+	return nil
+}
+
+// fakeBegin returns a begin function that records all started transactions.
+func fakeBegin(txs *[]*fakeTx, options *pgx.TxOptions) func(context.Context, pgx.TxOptions) (pgx.Tx, error) {
+	return func(_ context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
+		*options = txOptions
+		tx := &fakeTx{}
+		*txs = append(*txs, tx)
+		return tx, nil
+	}
+}
+
+func TestWithTx(t *testing.T) {
+	ctx := context.Background()
+	var txs []*fakeTx
+	var options pgx.TxOptions
+
+	opts := TxOptions{IsoLevel: Serializable, ReadOnly: true}
+	require.NoError(t, withTx(ctx, opts, fakeBegin(&txs, &options), nil, func(_ Tx) error {
+		return nil
+	}))
+	require.Len(t, txs, 1)
+	assert.True(t, txs[0].committed)
+	assert.False(t, txs[0].rolledBack)
+	assert.Equal(t, pgx.TxOptions{IsoLevel: pgx.Serializable, AccessMode: pgx.ReadOnly}, options)
+
+	txs = nil
+	errTest := errors.New("test")
+	err := withTx(ctx, TxOptions{}, fakeBegin(&txs, &options), nil, func(_ Tx) error {
+		return errTest
+	})
+	require.ErrorIs(t, err, errTest)
+	require.Len(t, txs, 1)
+	assert.False(t, txs[0].committed)
+	assert.True(t, txs[0].rolledBack)
+
+	txs = nil
+	assert.Panics(t, func() {
+		_ = withTx(ctx, TxOptions{}, fakeBegin(&txs, &options), nil, func(_ Tx) error {
+			panic("test")
+		})
+	})
+	require.Len(t, txs, 1)
+	assert.True(t, txs[0].rolledBack)
+}
+
+func TestWithTxRetry(t *testing.T) {
+	ctx := context.Background()
+	var txs []*fakeTx
+	var options pgx.TxOptions
+
+	calls := 0
+	opts := TxOptions{RetryDelay: time.Millisecond}
+	err := withTx(ctx, opts, fakeBegin(&txs, &options), nil, func(_ Tx) error {
+		calls++
+		if calls < 3 {
+			return &pgconn.PgError{Code: sqlStateSerializationFailure}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	require.Len(t, txs, 3)
+	assert.True(t, txs[2].committed)
+
+	// deadlocks when committing are retried until the retries are exhausted
+	calls = 0
+	begin := func(_ context.Context, _ pgx.TxOptions) (pgx.Tx, error) {
+		return &fakeTx{commitErr: &pgconn.PgError{Code: sqlStateDeadlockDetected}}, nil
+	}
+	opts.MaxRetries = 2
+	err = withTx(ctx, opts, begin, nil, func(_ Tx) error {
+		calls++
+		return nil
+	})
+	var pgErr *pgconn.PgError
+	require.ErrorAs(t, err, &pgErr)
+	assert.Equal(t, 3, calls)
+
+	// other errors are not retried
+	calls = 0
+	opts.MaxRetries = 0
+	err = withTx(ctx, opts, fakeBegin(&txs, &options), nil, func(_ Tx) error {
+		calls++
+		return &pgconn.PgError{Code: "23505"}
+	})
+	require.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestTxWithTx(t *testing.T) {
+	ctx := context.Background()
+	parent := &fakeTx{}
+	tx := Tx{Tx: parent}
+
+	errTest := errors.New("test")
+	err := tx.WithTx(ctx, func(_ Tx) error {
+		return errTest
+	})
+	require.ErrorIs(t, err, errTest)
+	require.NoError(t, tx.WithTx(ctx, func(_ Tx) error {
+		return nil
+	}))
+
+	require.Len(t, parent.savepoints, 2)
+	assert.True(t, parent.savepoints[0].rolledBack)
+	assert.True(t, parent.savepoints[1].committed)
+	assert.False(t, parent.committed)
+	assert.False(t, parent.rolledBack)
+}
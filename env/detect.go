@@ -0,0 +1,42 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DetectVariables contains the environment variables that Detect checks in order
+// for the name of the current environment.
+var DetectVariables = []string{"APP_ENV", "GO_ENV", "ENVIRONMENT", "ENV"}
+
+// DefaultEnvironment is returned by Detect if none of the variables is set. It
+// defaults to Production, so that a missing variable does not enable debug
+// features or destructive actions.
+var DefaultEnvironment = Production
+
+// Detect returns the environment that is set in the first non-empty variable of
+// DetectVariables, or DefaultEnvironment if none of them is set. An error is
+// returned if the variable contains an unknown environment.
+func Detect() (Environment, error) {
+	return DetectFrom(DetectVariables, DefaultEnvironment)
+}
+
+// DetectFrom returns the environment that is set in the first non-empty variable
+// of the given variables, or the fallback environment if none of them is set.
+func DetectFrom(variables []string, fallback Environment) (Environment, error) {
+	for _, variable := range variables {
+		value := strings.TrimSpace(os.Getenv(variable))
+		if value == "" {
+			continue
+		}
+
+		environment, err := Parse(value)
+		if err != nil {
+			return "", fmt.Errorf("detecting environment from %s: %w", variable, err)
+		}
+		return environment, nil
+	}
+
+	return fallback, nil
+}
@@ -0,0 +1,36 @@
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetect(t *testing.T) {
+	for _, variable := range DetectVariables {
+		t.Setenv(variable, "")
+	}
+
+	env, err := Detect()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultEnvironment, env)
+
+	t.Setenv("ENVIRONMENT", "qa")
+	env, err = Detect()
+	require.NoError(t, err)
+	assert.Equal(t, Qa, env)
+
+	t.Setenv("GO_ENV", "Production")
+	env, err = Detect()
+	require.NoError(t, err)
+	assert.Equal(t, Production, env)
+
+	t.Setenv("APP_ENV", "invalid")
+	_, err = Detect()
+	require.ErrorContains(t, err, "APP_ENV")
+
+	env, err = DetectFrom([]string{"MISSING_ENV_VARIABLE"}, Local)
+	require.NoError(t, err)
+	assert.Equal(t, Local, env)
+}
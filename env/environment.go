@@ -2,9 +2,14 @@
 package env
 
 import (
+	"encoding"
+	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
-	"strings"
+	"log/slog"
+	"net/http"
+	"slices"
 )
 
 // Environment defines a runtime environment.
@@ -20,38 +25,142 @@ const (
 	Production  Environment = "prod"
 )
 
+var (
+	_ encoding.TextMarshaler   = Environment("")
+	_ encoding.TextUnmarshaler = (*Environment)(nil)
+	_ json.Marshaler           = Environment("")
+	_ flag.Value               = (*Environment)(nil)
+	_ slog.LogValuer           = Environment("")
+)
+
 // ErrNotAllowed describes an error for action
 // which is not allowed in a given environment.
 var ErrNotAllowed = errors.New("action not allowed for the environment")
 
 // Parse returns an environment constant from a given string representation of it.
+// Registered environments and their aliases are accepted, see Register.
 func Parse(envName string) (Environment, error) {
-	switch Environment(strings.ToLower(envName)) {
-	case Local:
-		return Local, nil
+	environment, ok := resolve(envName)
+	if !ok {
+		return "", fmt.Errorf("unknown environment '%s'", envName)
+	}
+	return environment, nil
+}
 
-	case Test:
-		return Test, nil
+// Validate checks that the environment value is valid.
+func (env Environment) Validate() error {
+	_, err := Parse(string(env))
+	return err
+}
 
-	case Development:
-		return Development, nil
+// String returns the name of the environment.
+func (env Environment) String() string {
+	return string(env)
+}
 
-	case Qa:
-		return Qa, nil
+// Set parses the given environment name and sets the environment, it implements
+// the flag.Value interface.
+func (env *Environment) Set(value string) error {
+	parsed, err := Parse(value)
+	if err != nil {
+		return err
+	}
+	*env = parsed
+	return nil
+}
 
-	case Staging:
-		return Staging, nil
+// MarshalText returns the name of the environment.
+func (env Environment) MarshalText() ([]byte, error) {
+	return []byte(env), nil
+}
 
-	case Production, "production":
-		return Production, nil
+// UnmarshalText parses the given environment name and sets the environment.
+// Aliases of registered environments are resolved.
+func (env *Environment) UnmarshalText(text []byte) error {
+	return env.Set(string(text))
+}
 
-	default:
-		return "", fmt.Errorf("unknown environment '%s'", envName)
+// MarshalJSON returns the name of the environment as JSON string.
+func (env Environment) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(string(env))
+	if err != nil {
+		return nil, fmt.Errorf("marshaling environment: %w", err)
 	}
+	return data, nil
 }
 
-// Validate checks that the environment value is valid.
-func (env Environment) Validate() error {
-	_, err := Parse(string(env))
-	return err
+// LogValue returns the environment as string log value.
+func (env Environment) LogValue() slog.Value {
+	return slog.StringValue(string(env))
+}
+
+// IsProductionLike returns whether the environment is production or an environment
+// like staging that is treated like production and has to be protected from
+// destructive actions.
+// The classification of custom environments is set when registering them.
+func (env Environment) IsProductionLike() bool {
+	definition, ok := Lookup(env)
+	return ok && definition.ProductionLike
+}
+
+// Allow returns an error wrapping ErrNotAllowed if the environment is not one of
+// the given environments. Aliases are resolved, unknown environments are not allowed.
+func (env Environment) Allow(envs ...Environment) error {
+	if resolved, err := Parse(string(env)); err == nil && slices.Contains(resolveAll(envs), resolved) {
+		return nil
+	}
+	return fmt.Errorf("%w: '%s' is not one of %v", ErrNotAllowed, env, envs)
+}
+
+// Deny returns an error wrapping ErrNotAllowed if the environment is one of
+// the given environments. Aliases are resolved, unknown environments are denied.
+func (env Environment) Deny(envs ...Environment) error {
+	resolved, err := Parse(string(env))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrNotAllowed, err)
+	}
+	if !slices.Contains(resolveAll(envs), resolved) {
+		return nil
+	}
+	return fmt.Errorf("%w: '%s'", ErrNotAllowed, env)
+}
+
+// GuardDestructive returns an error wrapping ErrNotAllowed if the environment is
+// production-like or not registered, which includes an empty environment. It should
+// be called before executing destructive actions like seeding test data or resetting
+// a database, the action is included in the error.
+func (env Environment) GuardDestructive(action string) error {
+	if err := env.Validate(); err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrNotAllowed, action, err)
+	}
+	if !env.IsProductionLike() {
+		return nil
+	}
+	return fmt.Errorf("%w: %s in '%s'", ErrNotAllowed, action, env)
+}
+
+// GuardHandler returns a handler that responds with 403 Forbidden in production-like
+// or not registered environments and calls the given handler otherwise. It is intended
+// to protect debug endpoints like pprof handlers.
+func (env Environment) GuardHandler(handler http.Handler) http.Handler {
+	if env.GuardDestructive("") == nil {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := env.GuardDestructive(r.URL.Path)
+		http.Error(w, err.Error(), http.StatusForbidden)
+	})
+}
+
+// resolveAll returns the registered environments of the given environments, unknown
+// environments are skipped.
+func resolveAll(envs []Environment) []Environment {
+	resolved := make([]Environment, 0, len(envs))
+	for _, env := range envs {
+		if environment, err := Parse(string(env)); err == nil {
+			resolved = append(resolved, environment)
+		}
+	}
+	return resolved
 }
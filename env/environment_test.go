@@ -1,6 +1,11 @@
 package env
 
 import (
+	"encoding/json"
+	"flag"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -20,3 +25,92 @@ func TestValidate(t *testing.T) {
 	env := Environment("invalid")
 	require.Error(t, env.Validate())
 }
+
+func TestEncoding(t *testing.T) {
+	var env Environment
+	require.NoError(t, env.UnmarshalText([]byte("production")))
+	assert.Equal(t, Production, env)
+	require.Error(t, env.UnmarshalText([]byte("invalid")))
+
+	text, err := env.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "prod", string(text))
+
+	payload := struct {
+		Environment Environment `json:"environment"`
+	}{}
+	require.NoError(t, json.Unmarshal([]byte(`{"environment":"STAGING"}`), &payload))
+	assert.Equal(t, Staging, payload.Environment)
+	data, err := json.Marshal(payload)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"environment":"staging"}`, string(data))
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&env, "env", "environment")
+	require.NoError(t, fs.Parse([]string{"-env", "dev"}))
+	assert.Equal(t, Development, env)
+	assert.Equal(t, "dev", env.String())
+
+	assert.Equal(t, slog.StringValue("dev"), env.LogValue())
+}
+
+func TestIsProductionLike(t *testing.T) {
+	assert.True(t, Production.IsProductionLike())
+	assert.True(t, Staging.IsProductionLike())
+	assert.False(t, Qa.IsProductionLike())
+	assert.False(t, Development.IsProductionLike())
+	assert.False(t, Local.IsProductionLike())
+	assert.False(t, Test.IsProductionLike())
+}
+
+func TestAllowDeny(t *testing.T) {
+	require.NoError(t, Test.Allow(Local, Test))
+	err := Production.Allow(Local, Test)
+	require.ErrorIs(t, err, ErrNotAllowed)
+	assert.Equal(t, "action not allowed for the environment: 'prod' is not one of [local test]", err.Error())
+
+	require.NoError(t, Test.Deny(Staging, Production))
+	require.ErrorIs(t, Staging.Deny(Staging, Production), ErrNotAllowed)
+
+	// aliases and case are resolved
+	require.ErrorIs(t, Environment("production").Deny(Production), ErrNotAllowed)
+	require.ErrorIs(t, Environment("PROD").Deny(Environment("production")), ErrNotAllowed)
+	require.NoError(t, Environment("production").Allow(Production))
+
+	// unknown environments are neither allowed nor pass a deny list
+	for _, environment := range []Environment{"", "prodd"} {
+		require.ErrorIs(t, environment.Allow(Local, Environment("prodd")), ErrNotAllowed)
+		require.ErrorIs(t, environment.Deny(Production), ErrNotAllowed)
+	}
+}
+
+func TestGuardDestructive(t *testing.T) {
+	require.NoError(t, Development.GuardDestructive("seeding test data"))
+
+	err := Production.GuardDestructive("seeding test data")
+	require.ErrorIs(t, err, ErrNotAllowed)
+	assert.Contains(t, err.Error(), "seeding test data in 'prod'")
+
+	require.ErrorIs(t, Environment("production").GuardDestructive("drop"), ErrNotAllowed)
+	require.ErrorIs(t, Environment("").GuardDestructive("drop"), ErrNotAllowed)
+	require.ErrorIs(t, Environment("prodd").GuardDestructive("drop"), ErrNotAllowed)
+}
+
+func TestGuardHandler(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	for environment, status := range map[Environment]int{
+		Development: http.StatusOK,
+		Staging:     http.StatusForbidden,
+		Production:  http.StatusForbidden,
+		"":          http.StatusForbidden,
+		"prodd":     http.StatusForbidden,
+	} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil)
+		environment.GuardHandler(handler).ServeHTTP(rec, req)
+		assert.Equal(t, status, rec.Code, environment)
+	}
+}
@@ -0,0 +1,173 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Definition describes a registered environment.
+type Definition struct {
+	// Aliases are alternative names of the environment that Parse accepts.
+	Aliases []string
+	// ProductionLike defines whether the environment is treated like production
+	// and has to be protected from destructive actions.
+	ProductionLike bool
+}
+
+var (
+	registryMu sync.RWMutex
+
+	// registry contains all known environments.
+	registry = defaultRegistry()
+	// aliases maps all aliases to their environments.
+	aliases = defaultAliases()
+)
+
+// defaultRegistry returns the definitions of the built-in environments.
+func defaultRegistry() map[Environment]Definition {
+	return map[Environment]Definition{
+		Local:       {},
+		Test:        {},
+		Development: {},
+		Qa:          {},
+		Staging:     {ProductionLike: true},
+		Production:  {Aliases: []string{"production"}, ProductionLike: true},
+	}
+}
+
+// defaultAliases returns the aliases of the built-in environments.
+func defaultAliases() map[string]Environment {
+	return map[string]Environment{
+		"production": Production,
+	}
+}
+
+// reset removes all registered environments and aliases, only the built-in
+// environments are kept. It is intended for tests.
+func reset() {
+	registryMu.Lock()
+	registry = defaultRegistry()
+	aliases = defaultAliases()
+	registryMu.Unlock()
+}
+
+// Register registers an additional environment or replaces the definition of an
+// already registered one. Environment names and aliases are case-insensitive and
+// must not conflict with the names or aliases of other environments.
+func Register(environment Environment, definition Definition) error {
+	environment = Environment(strings.ToLower(string(environment)))
+	if strings.TrimSpace(string(environment)) == "" {
+		return errors.New("empty environment name")
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if existing, ok := aliases[string(environment)]; ok && existing != environment {
+		return fmt.Errorf("environment '%s' is already an alias of '%s'", environment, existing)
+	}
+
+	definition.Aliases = slices.Clone(definition.Aliases)
+	for i, alias := range definition.Aliases {
+		alias = strings.ToLower(alias)
+		if err := checkAlias(alias, environment); err != nil {
+			return err
+		}
+		definition.Aliases[i] = alias
+	}
+
+	// remove aliases of a replaced definition
+	for _, alias := range registry[environment].Aliases {
+		delete(aliases, alias)
+	}
+
+	registry[environment] = definition
+	for _, alias := range definition.Aliases {
+		aliases[alias] = environment
+	}
+	return nil
+}
+
+// RegisterAlias registers an additional alias for a registered environment.
+func RegisterAlias(alias string, environment Environment) error {
+	alias = strings.ToLower(alias)
+	environment = Environment(strings.ToLower(string(environment)))
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	definition, ok := registry[environment]
+	if !ok {
+		return fmt.Errorf("unknown environment '%s'", environment)
+	}
+	if err := checkAlias(alias, environment); err != nil {
+		return err
+	}
+
+	if !slices.Contains(definition.Aliases, alias) {
+		definition.Aliases = append(slices.Clone(definition.Aliases), alias)
+		registry[environment] = definition
+	}
+	aliases[alias] = environment
+	return nil
+}
+
+// checkAlias returns an error if the alias is empty or used by another environment.
+// The caller must hold the registry lock.
+func checkAlias(alias string, environment Environment) error {
+	if strings.TrimSpace(alias) == "" {
+		return fmt.Errorf("empty alias for environment '%s'", environment)
+	}
+	if _, ok := registry[Environment(alias)]; ok {
+		return fmt.Errorf("alias '%s' is already an environment name", alias)
+	}
+	if existing, ok := aliases[alias]; ok && existing != environment {
+		return fmt.Errorf("alias '%s' is already used by environment '%s'", alias, existing)
+	}
+	return nil
+}
+
+// Lookup returns the definition of a registered environment, the environment can
+// also be given by one of its aliases.
+func Lookup(environment Environment) (Definition, bool) {
+	environment, ok := resolve(string(environment))
+	if !ok {
+		return Definition{}, false
+	}
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	definition := registry[environment]
+	definition.Aliases = slices.Clone(definition.Aliases)
+	return definition, true
+}
+
+// Environments returns all registered environments in sorted order.
+func Environments() []Environment {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	environments := make([]Environment, 0, len(registry))
+	for environment := range registry {
+		environments = append(environments, environment)
+	}
+	slices.Sort(environments)
+	return environments
+}
+
+// resolve returns the registered environment for a name or alias.
+func resolve(name string) (Environment, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	name = strings.ToLower(name)
+	if _, ok := registry[Environment(name)]; ok {
+		return Environment(name), true
+	}
+	environment, ok := aliases[name]
+	return environment, ok
+}
@@ -0,0 +1,57 @@
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegister(t *testing.T) {
+	t.Cleanup(reset)
+
+	require.NoError(t, Register("Canary", Definition{Aliases: []string{"Canary-Release"}, ProductionLike: true}))
+	require.NoError(t, Register("sandbox", Definition{}))
+	require.NoError(t, RegisterAlias("stage", Staging))
+	require.NoError(t, RegisterAlias("development", Development))
+
+	env, err := Parse("canary-release")
+	require.NoError(t, err)
+	assert.Equal(t, Environment("canary"), env)
+	assert.True(t, env.IsProductionLike())
+
+	env, err = Parse("STAGE")
+	require.NoError(t, err)
+	assert.Equal(t, Staging, env)
+
+	env, err = Parse("development")
+	require.NoError(t, err)
+	assert.Equal(t, Development, env)
+
+	sandbox := Environment("sandbox")
+	require.NoError(t, sandbox.Validate())
+	assert.False(t, sandbox.IsProductionLike())
+	assert.Contains(t, Environments(), sandbox)
+
+	definition, ok := Lookup("production")
+	require.True(t, ok)
+	assert.True(t, definition.ProductionLike)
+
+	// conflicting names and aliases are rejected
+	require.Error(t, Register("", Definition{}))
+	require.Error(t, Register("stage", Definition{}))
+	require.Error(t, Register("perf", Definition{Aliases: []string{"prod"}}))
+	require.Error(t, Register("perf", Definition{Aliases: []string{"stage"}}))
+	require.Error(t, RegisterAlias("perf", "unknown"))
+	require.Error(t, RegisterAlias("stage", Production))
+
+	_, err = Parse("perf")
+	require.Error(t, err)
+
+	reset()
+	_, err = Parse("canary")
+	require.Error(t, err)
+	_, err = Parse("stage")
+	require.Error(t, err)
+	require.NoError(t, Production.Validate())
+}
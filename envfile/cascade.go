@@ -0,0 +1,84 @@
+package envfile
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cornelk/gotokit/env"
+)
+
+const localSuffix = ".local"
+
+// CascadeOptions defines options for loading the env file cascade.
+type CascadeOptions struct {
+	// NoOverride keeps the variables that are set in the process environment before
+	// loading. Files of the cascade still override the values of earlier files.
+	NoOverride bool
+
+	// SearchRoot enables loading the files from the project root directory, which is
+	// found by walking up from the current directory, see FindRoot. Files of the root
+	// directory are loaded first and can be overridden by the files of the current and
+	// executable directories. This allows finding the env files of a repository when
+	// running tests of subpackages. If no root is found, only the current and executable
+	// directories are used.
+	SearchRoot bool
+	// RootMarkers are the file names that mark the project root directory, defaults
+	// to DefaultRootMarkers.
+	RootMarkers []string
+}
+
+// CascadeFiles returns the env file names for the given environment in increasing
+// precedence: .env, .env.local, .env.<environment> and .env.<environment>.local.
+// Only the first two files are returned for an empty environment.
+func CascadeFiles(environment env.Environment) []string {
+	files := []string{envFileName, envFileName + localSuffix}
+	if environment != "" {
+		environmentFile := envFileName + "." + string(environment)
+		files = append(files, environmentFile, environmentFile+localSuffix)
+	}
+	return files
+}
+
+// LoadEnvironment loads the env file cascade for the given environment from the
+// current and executable directories, see CascadeFiles. Later files override the
+// values of earlier files, regardless of the directory that they were found in.
+// For the same file name, the executable directory overrides the current directory.
+// Missing files are skipped. Aliases of the environment are
+// resolved, the files use the name of the environment like .env.prod.
+func LoadEnvironment(environment env.Environment, opts CascadeOptions) (*Result, error) {
+	if environment != "" {
+		var err error
+		environment, err = env.Parse(string(environment))
+		if err != nil {
+			return nil, fmt.Errorf("parsing environment: %w", err)
+		}
+	}
+
+	var protected map[string]struct{}
+	if opts.NoOverride {
+		protected = processVariables()
+	}
+
+	var root string
+	if opts.SearchRoot {
+		var err error
+		root, err = findRootFromWorkingDirectory(opts.RootMarkers)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return loadFiles(root, protected, CascadeFiles(environment)...)
+}
+
+// processVariables returns the names of all variables of the process environment.
+func processVariables() map[string]struct{} {
+	environ := os.Environ()
+	variables := make(map[string]struct{}, len(environ))
+	for _, variable := range environ {
+		key, _, _ := strings.Cut(variable, "=")
+		variables[key] = struct{}{}
+	}
+	return variables
+}
@@ -0,0 +1,87 @@
+package envfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cornelk/gotokit/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCascadeFiles(t *testing.T) {
+	assert.Equal(t, []string{".env", ".env.local"}, CascadeFiles(""))
+	assert.Equal(t, []string{".env", ".env.local", ".env.prod", ".env.prod.local"}, CascadeFiles(env.Production))
+}
+
+func TestLoadEnvironment(t *testing.T) {
+	t.Setenv("CASCADE_BASE", "")
+	t.Setenv("CASCADE_LOCAL", "")
+	t.Setenv("CASCADE_ENV", "")
+	t.Setenv("CASCADE_PROCESS", "process")
+	for _, key := range []string{"CASCADE_BASE", "CASCADE_LOCAL", "CASCADE_ENV"} {
+		require.NoError(t, os.Unsetenv(key))
+	}
+
+	tmpdir := t.TempDir()
+	files := map[string]string{
+		".env":               "CASCADE_BASE=base\nCASCADE_LOCAL=base\nCASCADE_ENV=base\nCASCADE_PROCESS=base\n",
+		".env.local":         "CASCADE_LOCAL=local\n",
+		".env.staging":       "CASCADE_ENV=staging\n",
+		".env.staging.local": "CASCADE_PROCESS=staging\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpdir, name), []byte(content), 0644))
+	}
+	chdir(t, tmpdir)
+
+	result, err := LoadEnvironment(env.Staging, CascadeOptions{NoOverride: true})
+	require.NoError(t, err)
+	assert.Equal(t, "base", os.Getenv("CASCADE_BASE"))
+	assert.Equal(t, "local", os.Getenv("CASCADE_LOCAL"))
+	assert.Equal(t, "staging", os.Getenv("CASCADE_ENV"))
+	assert.Equal(t, "process", os.Getenv("CASCADE_PROCESS"))
+
+	require.NotEmpty(t, result.Files)
+	assert.Equal(t, filepath.Join(tmpdir, ".env"), result.Files[0].Path)
+	assert.Equal(t, []string{"CASCADE_PROCESS"}, result.Files[0].Skipped)
+
+	_, err = LoadEnvironment(env.Staging, CascadeOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "staging", os.Getenv("CASCADE_PROCESS"))
+
+	_, err = LoadEnvironment("invalid", CascadeOptions{})
+	require.Error(t, err)
+}
+
+func TestLoadEnvironmentDirectoryOrder(t *testing.T) {
+	t.Setenv("CASCADE_ORDER", "")
+	require.NoError(t, os.Unsetenv("CASCADE_ORDER"))
+
+	// a later file of an earlier directory overrides an earlier file of a later directory
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".env.local"), []byte("CASCADE_ORDER=local\n"), 0644))
+	current := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(current, ".env"), []byte("CASCADE_ORDER=base\n"), 0644))
+	chdir(t, current)
+
+	result, err := loadFiles(root, nil, CascadeFiles("")...)
+	require.NoError(t, err)
+	assert.Equal(t, "local", os.Getenv("CASCADE_ORDER"))
+	require.Len(t, result.Files, 2)
+	assert.Equal(t, filepath.Join(current, ".env"), result.Files[0].Path)
+}
+
+// chdir changes the working directory to the given directory and restores the
+// previous working directory when the test finishes.
+func chdir(t *testing.T, directory string) {
+	t.Helper()
+
+	previous, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(directory))
+	t.Cleanup(func() {
+		_ = os.Chdir(previous)
+	})
+}
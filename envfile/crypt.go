@@ -0,0 +1,278 @@
+package envfile
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Key sources for encrypted env files.
+const (
+	KeyVariable     = "ENVFILE_KEY"      // variable that contains the hex encoded key
+	KeyFileVariable = "ENVFILE_KEY_FILE" // variable that contains the path of the key file
+	KeyFileName     = ".env.key"         // key file that is looked up next to the env file
+)
+
+// encryptedPrefix marks encrypted values. The value is followed by the base64 encoded
+// nonce and ciphertext of the value as written in the file, including quotes.
+const encryptedPrefix = "encrypted:"
+
+const keySize = 32 // AES-256
+
+var (
+	// ErrMissingKey is returned if an env file contains encrypted values but no key is set.
+	ErrMissingKey = errors.New("missing encryption key")
+	// ErrInvalidKey is returned for keys that are not 32 hex encoded bytes.
+	ErrInvalidKey = errors.New("invalid encryption key")
+	// ErrDecryption is returned if an encrypted value can not be decrypted, either
+	// because the key is wrong or the value was modified.
+	ErrDecryption = errors.New("decrypting value failed")
+)
+
+// GenerateKey returns a new random hex encoded key for encrypting env files.
+func GenerateKey() (string, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("generating key: %w", err)
+	}
+	return hex.EncodeToString(key), nil
+}
+
+// LookupKey returns the key for the env file in the given directory. It is read from
+// the ENVFILE_KEY variable, the file set in the ENVFILE_KEY_FILE variable or the
+// .env.key file in the directory, in this order.
+func LookupKey(directory string) (string, error) {
+	if key := os.Getenv(KeyVariable); key != "" {
+		return key, nil
+	}
+
+	keyFile := os.Getenv(KeyFileVariable)
+	if keyFile == "" {
+		keyFile = filepath.Join(directory, KeyFileName)
+	}
+
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("%w: set %s or create %s", ErrMissingKey, KeyVariable, keyFile)
+		}
+		return "", fmt.Errorf("reading key file: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// Encrypt encrypts all values of the given env file content using AES-256-GCM with the
+// given hex encoded key. Keys, their order, comments and empty lines are preserved,
+// already encrypted values are kept.
+func Encrypt(data []byte, key string) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return rewriteValues(data, func(e entry) (string, error) {
+		if isEncrypted(e) {
+			return e.raw, nil
+		}
+		return encryptValue(aead, e.key, e.raw)
+	})
+}
+
+// Decrypt decrypts all encrypted values of the given env file content using the given
+// hex encoded key and returns the content with the values as originally written.
+func Decrypt(data []byte, key string) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return rewriteValues(data, func(e entry) (string, error) {
+		if !isEncrypted(e) {
+			return e.raw, nil
+		}
+		return decryptValue(aead, e)
+	})
+}
+
+// Edit decrypts the given env file, passes the plain content to the edit function and
+// writes the encrypted result back to the file. Values that were not changed keep their
+// encrypted form, which keeps diffs of committed files small.
+func Edit(path, key string, edit func(plain []byte) ([]byte, error)) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("getting file info: %w", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading env file: %w", err)
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	// remember the encrypted form of all values to reuse it for unchanged values
+	previous := map[string]map[string]string{}
+	plain, err := rewriteValues(data, func(e entry) (string, error) {
+		if !isEncrypted(e) {
+			return e.raw, nil
+		}
+		raw, err := decryptValue(aead, e)
+		if err != nil {
+			return "", err
+		}
+		if previous[e.key] == nil {
+			previous[e.key] = map[string]string{}
+		}
+		previous[e.key][raw] = e.raw
+		return raw, nil
+	})
+	if err != nil {
+		return withFile(err, path)
+	}
+
+	edited, err := edit(plain)
+	if err != nil {
+		return err
+	}
+
+	encrypted, err := rewriteValues(edited, func(e entry) (string, error) {
+		if isEncrypted(e) {
+			return e.raw, nil
+		}
+		if raw, ok := previous[e.key][e.raw]; ok {
+			return raw, nil
+		}
+		return encryptValue(aead, e.key, e.raw)
+	})
+	if err != nil {
+		return withFile(err, path)
+	}
+
+	if err := os.WriteFile(path, encrypted, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("writing env file: %w", err)
+	}
+	return nil
+}
+
+// decryptEntries decrypts all encrypted values of the entries of the given file. The
+// key is only looked up if the file contains encrypted values.
+func decryptEntries(file string, entries []entry) error {
+	var aead cipher.AEAD
+
+	for i, e := range entries {
+		if !isEncrypted(e) {
+			continue
+		}
+
+		if aead == nil {
+			key, err := LookupKey(filepath.Dir(file))
+			if err != nil {
+				return &ParseError{File: file, Line: e.line, Err: err}
+			}
+			if aead, err = newAEAD(key); err != nil {
+				return &ParseError{File: file, Line: e.line, Err: err}
+			}
+		}
+
+		raw, err := decryptValue(aead, e)
+		if err != nil {
+			return &ParseError{File: file, Line: e.line, Err: err}
+		}
+
+		// parse the decrypted value to handle quotes and escape sequences
+		decrypted, err := parse([]byte(e.key + "=" + raw))
+		if err != nil || len(decrypted) != 1 {
+			return &ParseError{File: file, Line: e.line, Err: fmt.Errorf("%w: invalid decrypted value", ErrSyntax)}
+		}
+		entries[i].value = decrypted[0].value
+		entries[i].quote = decrypted[0].quote
+	}
+
+	return nil
+}
+
+// rewriteValues replaces the values of all assignments of the env file content with
+// the result of the given function. All other lines are kept.
+func rewriteValues(data []byte, rewrite func(e entry) (string, error)) ([]byte, error) {
+	entries, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := splitLines(data)
+	result := make([]string, 0, len(lines))
+	next := 0
+
+	for _, e := range entries {
+		result = append(result, lines[next:e.line-1]...)
+		next = e.end
+
+		value, err := rewrite(e)
+		if err != nil {
+			return nil, &ParseError{Line: e.line, Err: err}
+		}
+		result = append(result, e.prefix+value+e.suffix)
+	}
+	result = append(result, lines[next:]...)
+
+	return []byte(strings.Join(result, "\n")), nil
+}
+
+// isEncrypted returns whether the entry contains an encrypted value.
+func isEncrypted(e entry) bool {
+	return e.quote == 0 && strings.HasPrefix(e.value, encryptedPrefix)
+}
+
+// newAEAD returns an AES-GCM cipher for the given hex encoded key.
+func newAEAD(key string) (cipher.AEAD, error) {
+	decoded, err := hex.DecodeString(strings.TrimSpace(key))
+	if err != nil || len(decoded) != keySize {
+		return nil, fmt.Errorf("%w: expected %d hex encoded bytes", ErrInvalidKey, keySize)
+	}
+
+	block, err := aes.NewCipher(decoded)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("creating GCM: %w", err)
+	}
+	return aead, nil
+}
+
+// encryptValue returns the encrypted form of a raw value. The variable name is used as
+// additional data, which prevents moving encrypted values between variables.
+func encryptValue(aead cipher.AEAD, name, raw string) (string, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generating nonce: %w", err)
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(raw), []byte(name))
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decryptValue returns the raw value of an encrypted entry.
+func decryptValue(aead cipher.AEAD, e entry) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(e.value, encryptedPrefix))
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("%w: invalid encoding of %s", ErrDecryption, e.key)
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	raw, err := aead.Open(nil, nonce, ciphertext, []byte(e.key))
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrDecryption, e.key)
+	}
+	return string(raw), nil
+}
@@ -0,0 +1,119 @@
+package envfile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const plainEnvFile = "# database settings\n" +
+	"DATABASE_HOST=localhost # inline comment\n" +
+	"\n" +
+	"export DATABASE_PASSWORD=\"multi\n" +
+	"line\"\n" +
+	"DATABASE_URL='postgres://${DATABASE_HOST}'\n"
+
+func TestEncryptDecrypt(t *testing.T) {
+	t.Parallel()
+
+	key, err := GenerateKey()
+	require.NoError(t, err)
+
+	encrypted, err := Encrypt([]byte(plainEnvFile), key)
+	require.NoError(t, err)
+
+	lines := strings.Split(string(encrypted), "\n")
+	require.Len(t, lines, 6)
+	assert.Equal(t, "# database settings", lines[0])
+	assert.Regexp(t, `^DATABASE_HOST=encrypted:\S+ # inline comment$`, lines[1])
+	assert.Empty(t, lines[2])
+	assert.Regexp(t, `^export DATABASE_PASSWORD=encrypted:\S+$`, lines[3])
+	assert.NotContains(t, string(encrypted), "localhost")
+
+	// encrypting again keeps the encrypted values
+	again, err := Encrypt(encrypted, key)
+	require.NoError(t, err)
+	assert.Equal(t, encrypted, again)
+
+	decrypted, err := Decrypt(encrypted, key)
+	require.NoError(t, err)
+	assert.Equal(t, plainEnvFile, string(decrypted))
+
+	otherKey, err := GenerateKey()
+	require.NoError(t, err)
+	_, err = Decrypt(encrypted, otherKey)
+	require.ErrorIs(t, err, ErrDecryption)
+
+	_, err = Encrypt([]byte(plainEnvFile), "invalid")
+	require.ErrorIs(t, err, ErrInvalidKey)
+
+	// values can not be moved between variables
+	moved := strings.Replace(string(encrypted), "DATABASE_HOST", "OTHER_HOST", 1)
+	_, err = Decrypt([]byte(moved), key)
+	require.ErrorIs(t, err, ErrDecryption)
+}
+
+func TestReadEncrypted(t *testing.T) {
+	key, err := GenerateKey()
+	require.NoError(t, err)
+	encrypted, err := Encrypt([]byte(plainEnvFile), key)
+	require.NoError(t, err)
+
+	tmpdir := t.TempDir()
+	file := filepath.Join(tmpdir, ".envprivate")
+	require.NoError(t, os.WriteFile(file, encrypted, 0600))
+	t.Setenv(KeyVariable, "")
+	t.Setenv(KeyFileVariable, "")
+
+	_, err = Read(file)
+	require.ErrorIs(t, err, ErrMissingKey)
+
+	require.NoError(t, os.WriteFile(filepath.Join(tmpdir, KeyFileName), []byte(key+"\n"), 0600))
+	variables, err := Read(file)
+	require.NoError(t, err)
+	expected := map[string]string{
+		"DATABASE_HOST":     "localhost",
+		"DATABASE_PASSWORD": "multi\nline",
+		"DATABASE_URL":      "postgres://${DATABASE_HOST}",
+	}
+	assert.Equal(t, expected, variables)
+}
+
+func TestEdit(t *testing.T) {
+	t.Parallel()
+
+	key, err := GenerateKey()
+	require.NoError(t, err)
+	encrypted, err := Encrypt([]byte(plainEnvFile), key)
+	require.NoError(t, err)
+
+	file := filepath.Join(t.TempDir(), ".envprivate")
+	require.NoError(t, os.WriteFile(file, encrypted, 0600))
+
+	err = Edit(file, key, func(plain []byte) ([]byte, error) {
+		assert.Equal(t, plainEnvFile, string(plain))
+		return []byte(strings.Replace(string(plain), "localhost", "remotehost", 1) + "NEW=value\n"), nil
+	})
+	require.NoError(t, err)
+
+	edited, err := os.ReadFile(file)
+	require.NoError(t, err)
+	editedLines := strings.Split(string(edited), "\n")
+	encryptedLines := strings.Split(string(encrypted), "\n")
+	assert.NotEqual(t, encryptedLines[1], editedLines[1])
+	assert.Equal(t, encryptedLines[3], editedLines[3], "unchanged values keep their encrypted form")
+	assert.Regexp(t, `^NEW=encrypted:\S+$`, editedLines[5])
+
+	decrypted, err := Decrypt(edited, key)
+	require.NoError(t, err)
+	assert.Contains(t, string(decrypted), "DATABASE_HOST=remotehost # inline comment\n")
+	assert.Contains(t, string(decrypted), "NEW=value\n")
+
+	info, err := os.Stat(file)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
@@ -0,0 +1,261 @@
+package envfile
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrMissingVariable is returned for ${VAR:?error} references of variables that are not set.
+	ErrMissingVariable = errors.New("missing variable")
+	// ErrReferenceCycle is returned for variables that reference themselves.
+	ErrReferenceCycle = errors.New("variable reference cycle")
+)
+
+// interpolator expands the variable references of env file entries. The following
+// forms are supported, a backslash before the dollar sign escapes a reference. In
+// double quoted values an escaped backslash \\ is resolved to a single backslash:
+//
+//	$VAR, ${VAR}    value of VAR
+//	${VAR:-default} default if VAR is not set or empty, ${VAR-default} only if not set
+//	${VAR:?error}   error if VAR is not set or empty, ${VAR?error} only if not set
+//
+// References are resolved against the earlier entries of the file, the given
+// lookup function, which is usually backed by the process environment containing
+// previously loaded files, and finally the later entries of the file.
+type interpolator struct {
+	file      string
+	entries   []entry
+	lookupEnv func(string) (string, bool)
+	protected map[string]struct{} // variables that are resolved using lookupEnv only
+
+	values map[int]string // expanded values by entry index
+	active []int          // indexes of the entries that are being expanded
+}
+
+// newInterpolator returns an interpolator for the entries of the given file.
+func newInterpolator(file string, entries []entry, lookupEnv func(string) (string, bool),
+	protected map[string]struct{}) *interpolator {
+
+	return &interpolator{
+		file:      file,
+		entries:   entries,
+		lookupEnv: lookupEnv,
+		protected: protected,
+		values:    make(map[int]string, len(entries)),
+	}
+}
+
+// expandAll returns the expanded values of all entries in the order of the entries.
+func (ip *interpolator) expandAll() ([]string, error) {
+	values := make([]string, len(ip.entries))
+	for i := range ip.entries {
+		value, err := ip.expand(i)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// expand returns the expanded value of the entry with the given index.
+func (ip *interpolator) expand(index int) (string, error) {
+	if value, ok := ip.values[index]; ok {
+		return value, nil
+	}
+
+	e := ip.entries[index]
+	for i, active := range ip.active {
+		if active != index {
+			continue
+		}
+
+		chain := make([]string, 0, len(ip.active)-i+1)
+		for _, active := range ip.active[i:] {
+			chain = append(chain, ip.entries[active].key)
+		}
+		chain = append(chain, e.key)
+		return "", &ParseError{
+			File: ip.file,
+			Line: e.line,
+			Err:  fmt.Errorf("%w: %s", ErrReferenceCycle, strings.Join(chain, " -> ")),
+		}
+	}
+
+	value := e.value
+	if e.quote != '\'' {
+		ip.active = append(ip.active, index)
+		var err error
+		value, err = ip.interpolate(value, index)
+		ip.active = ip.active[:len(ip.active)-1]
+		if err != nil {
+			var parseErr *ParseError
+			if errors.As(err, &parseErr) {
+				return "", err
+			}
+			return "", &ParseError{File: ip.file, Line: e.line, Err: err}
+		}
+	}
+
+	ip.values[index] = value
+	return value, nil
+}
+
+// interpolate expands all references in the given text of the entry with the
+// given index.
+func (ip *interpolator) interpolate(text string, index int) (string, error) {
+	doubleQuoted := ip.entries[index].quote == '"'
+	var b strings.Builder
+
+	for i := 0; i < len(text); i++ {
+		char := text[i]
+		var next byte
+		if i+1 < len(text) {
+			next = text[i+1]
+		}
+
+		switch {
+		case char == '\\' && (next == '$' || (doubleQuoted && next == '\\')):
+			b.WriteByte(next)
+			i++
+
+		case char == '$' && next == '{':
+			end := closingBrace(text, i+2)
+			if end == -1 {
+				return "", fmt.Errorf("%w: unterminated reference %q", ErrSyntax, text[i:])
+			}
+			value, err := ip.expression(text[i+2:end], index)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(value)
+			i = end
+
+		case char == '$' && isNameChar(rune(next)):
+			end := i + 1
+			for end < len(text) && isNameChar(rune(text[end])) {
+				end++
+			}
+			value, _, err := ip.lookup(text[i+1:end], index)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(value)
+			i = end - 1
+
+		default:
+			b.WriteByte(char)
+		}
+	}
+
+	return b.String(), nil
+}
+
+// expression returns the value of a ${...} expression without the braces.
+func (ip *interpolator) expression(expr string, index int) (string, error) {
+	end := 0
+	for end < len(expr) && isKeyChar(rune(expr[end])) {
+		end++
+	}
+	name, operator := expr[:end], expr[end:]
+	if name == "" {
+		return "", fmt.Errorf("%w: invalid reference ${%s}", ErrSyntax, expr)
+	}
+
+	value, ok, err := ip.lookup(name, index)
+	if err != nil {
+		return "", err
+	}
+
+	var argument string
+	switch {
+	case operator == "":
+		return value, nil
+
+	case strings.HasPrefix(operator, ":-"), strings.HasPrefix(operator, ":?"):
+		argument = operator[2:]
+		ok = ok && value != ""
+		operator = operator[1:2]
+
+	case strings.HasPrefix(operator, "-"), strings.HasPrefix(operator, "?"):
+		argument = operator[1:]
+		operator = operator[:1]
+
+	default:
+		return "", fmt.Errorf("%w: invalid reference ${%s}", ErrSyntax, expr)
+	}
+
+	if ok {
+		return value, nil
+	}
+
+	argument, err = ip.interpolate(argument, index)
+	if err != nil {
+		return "", err
+	}
+	if operator == "-" {
+		return argument, nil
+	}
+
+	if argument == "" {
+		argument = "not set"
+	}
+	return "", fmt.Errorf("%w: %s: %s", ErrMissingVariable, name, argument)
+}
+
+// lookup returns the value of the variable referenced by the entry with the given index.
+func (ip *interpolator) lookup(name string, index int) (string, bool, error) {
+	_, protected := ip.protected[name]
+
+	if !protected {
+		for i := index - 1; i >= 0; i-- {
+			if ip.entries[i].key == name {
+				value, err := ip.expand(i)
+				return value, true, err
+			}
+		}
+	}
+
+	if value, ok := ip.lookupEnv(name); ok {
+		return value, true, nil
+	}
+
+	if !protected {
+		for i := len(ip.entries) - 1; i > index; i-- {
+			if ip.entries[i].key == name {
+				value, err := ip.expand(i)
+				return value, true, err
+			}
+		}
+	}
+
+	return "", false, nil
+}
+
+// closingBrace returns the index of the brace that closes the expression starting
+// at the given index, or -1.
+func closingBrace(text string, start int) int {
+	depth := 0
+	for i := start; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++ // skip escaped character
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// isNameChar returns whether the character is valid in a $VAR reference.
+func isNameChar(char rune) bool {
+	return char == '_' || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') ||
+		(char >= '0' && char <= '9')
+}
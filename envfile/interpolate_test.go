@@ -0,0 +1,89 @@
+package envfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func interpolate(t *testing.T, data string, environment map[string]string) ([]string, error) {
+	t.Helper()
+
+	entries, err := parse([]byte(data))
+	require.NoError(t, err)
+
+	lookupEnv := func(key string) (string, bool) {
+		value, ok := environment[key]
+		return value, ok
+	}
+	return newInterpolator(".env", entries, lookupEnv, nil).expandAll()
+}
+
+func TestInterpolate(t *testing.T) {
+	data := "HOST=localhost\n" +
+		"URL=http://${HOST}:$PORT/${PATH_PREFIX:-api}\n" +
+		"EMPTY=\n" +
+		"DEFAULT_EMPTY=${EMPTY:-empty}\n" +
+		"DEFAULT_UNSET=${EMPTY-unset}${MISSING-unset}\n" +
+		"NESTED=${MISSING:-${HOST}}\n" +
+		"ESCAPED=\\$HOST \\${HOST}\n" +
+		"QUOTED=\"${HOST}\\$\"\n" +
+		"BACKSLASH=\"\\\\$HOST \\\\\\$HOST \\\\n\"\n" +
+		"UNQUOTED_BACKSLASH=\\\\$HOST\n" +
+		"SINGLE='${HOST}'\n" +
+		"FORWARD=${LATER}\n" +
+		"LATER=later\n" +
+		"SELF=${SELF}:extended\n" +
+		"DOLLAR=$ 100$\n"
+
+	values, err := interpolate(t, data, map[string]string{"PORT": "8080", "SELF": "env"})
+	require.NoError(t, err)
+
+	expected := []string{
+		"localhost",
+		"http://localhost:8080/api",
+		"",
+		"empty",
+		"unset",
+		"localhost",
+		"$HOST ${HOST}",
+		"localhost$",
+		`\localhost \$HOST \n`,
+		`\$HOST`,
+		"${HOST}",
+		"later",
+		"later",
+		"env:extended",
+		"$ 100$",
+	}
+	assert.Equal(t, expected, values)
+}
+
+func TestInterpolateErrors(t *testing.T) {
+	tests := []struct {
+		data string
+		line int
+		err  error
+	}{
+		{data: "A=1\nB=${MISSING:?must be set}\n", line: 2, err: ErrMissingVariable},
+		{data: "A=${B}\nB=${C}\nC=${A}\n", line: 1, err: ErrReferenceCycle},
+		{data: "A=${B\n", line: 1, err: ErrSyntax},
+		{data: "A=${B:+x}\n", line: 1, err: ErrSyntax},
+	}
+
+	for _, tt := range tests {
+		_, err := interpolate(t, tt.data, nil)
+		require.ErrorIs(t, err, tt.err, tt.data)
+
+		var parseErr *ParseError
+		require.ErrorAs(t, err, &parseErr, tt.data)
+		assert.Equal(t, ".env", parseErr.File)
+		assert.Equal(t, tt.line, parseErr.Line, tt.data)
+	}
+
+	_, err := interpolate(t, "A=${B}\nB=${C}\nC=${A}\n", nil)
+	assert.ErrorContains(t, err, "A -> B -> C -> A")
+	_, err = interpolate(t, "A=${MISSING:?must be set}\n", nil)
+	assert.ErrorContains(t, err, "MISSING: must be set")
+}
@@ -0,0 +1,249 @@
+package envfile
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Rule identifies the kind of a lint issue.
+type Rule string
+
+// Available lint rules.
+const (
+	RuleSyntax             Rule = "syntax"              // invalid syntax, the file can not be loaded
+	RuleDuplicateKey       Rule = "duplicate-key"       // key is defined multiple times
+	RuleKeyWhitespace      Rule = "key-whitespace"      // whitespace between key and separator
+	RuleTrailingWhitespace Rule = "trailing-whitespace" // whitespace at the end of a line
+	RuleUnquotedSpace      Rule = "unquoted-space"      // unquoted value contains spaces
+	RuleMissingKey         Rule = "missing-key"         // key of the example file is missing
+	RuleExtraKey           Rule = "extra-key"           // key is not defined in the example file
+)
+
+// Issue describes a problem found by Lint.
+type Issue struct {
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"` // 0 for issues that do not refer to a line
+	Key     string `json:"key,omitempty"`
+	Rule    Rule   `json:"rule"`
+	Message string `json:"message"`
+}
+
+// String returns the issue in the format file:line: rule: message.
+func (i Issue) String() string {
+	if i.Line == 0 {
+		return fmt.Sprintf("%s: %s: %s", i.File, i.Rule, i.Message)
+	}
+	return fmt.Sprintf("%s:%d: %s: %s", i.File, i.Line, i.Rule, i.Message)
+}
+
+// Issues is a list of lint issues. It can be encoded as JSON for machine processing.
+type Issues []Issue
+
+// String returns all issues, one per line.
+func (is Issues) String() string {
+	var b strings.Builder
+	for _, issue := range is {
+		b.WriteString(issue.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// LintOptions defines options for linting env files.
+type LintOptions struct {
+	// Example is the path of an example file like .env.example. If set, keys that
+	// are missing in the linted file or not defined in the example are reported.
+	Example string
+}
+
+// Lint checks the given env file for syntax problems, duplicate keys, whitespace
+// problems and, if an example file is set, for differences of the keys. The issues
+// are sorted by line, issues of the example file and issues without line follow.
+// Every line with a syntax error is reported and skipped, all other lines are still
+// checked. An error is only returned if a file can not be read.
+func Lint(file string, opts LintOptions) (Issues, error) {
+	issues, entries, err := lintFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Example != "" {
+		exampleIssues, exampleEntries, err := lintFile(opts.Example)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range exampleIssues {
+			if issue.Rule == RuleSyntax {
+				issues = append(issues, issue)
+			}
+		}
+		issues = append(issues, compareKeys(file, opts.Example, entries, exampleEntries)...)
+	}
+
+	// issues of the linted file first, issues without line at the end
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].File != issues[j].File {
+			return issues[i].File == file
+		}
+		if issues[i].Line == 0 || issues[j].Line == 0 {
+			return issues[j].Line == 0 && issues[i].Line != 0
+		}
+		return issues[i].Line < issues[j].Line
+	})
+	return issues, nil
+}
+
+// lintFile returns the issues and the parsed entries of a file.
+func lintFile(file string) (Issues, []entry, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading env file: %w", err)
+	}
+
+	// lines with syntax errors are skipped, all other lines are linted
+	var issues Issues
+	entries, errs := parseAll(data)
+	for _, err := range errs {
+		issues = append(issues, Issue{
+			File:    file,
+			Line:    err.Line,
+			Rule:    RuleSyntax,
+			Message: err.Err.Error(),
+		})
+	}
+
+	issues = append(issues, lintLines(file, splitLines(data), entries)...)
+	issues = append(issues, lintEntries(file, entries)...)
+	return issues, entries, nil
+}
+
+// lintLines reports trailing whitespace of all lines that are not part of a
+// multi line quoted value.
+func lintLines(file string, lines []string, entries []entry) Issues {
+	var issues Issues
+
+	quoted := map[int]struct{}{}
+	for _, e := range entries {
+		for line := e.line; line < e.end; line++ {
+			quoted[line] = struct{}{}
+		}
+	}
+
+	for i, line := range lines {
+		if _, ok := quoted[i+1]; ok {
+			continue
+		}
+		if trimmed := strings.TrimRightFunc(line, unicode.IsSpace); trimmed != line {
+			issues = append(issues, Issue{
+				File:    file,
+				Line:    i + 1,
+				Rule:    RuleTrailingWhitespace,
+				Message: "line ends with whitespace",
+			})
+		}
+	}
+
+	return issues
+}
+
+// lintEntries reports duplicate keys, whitespace between keys and separators and
+// unquoted values containing spaces.
+func lintEntries(file string, entries []entry) Issues {
+	var issues Issues
+	defined := map[string]int{}
+
+	for _, e := range entries {
+		if line, ok := defined[e.key]; ok {
+			issues = append(issues, Issue{
+				File:    file,
+				Line:    e.line,
+				Key:     e.key,
+				Rule:    RuleDuplicateKey,
+				Message: fmt.Sprintf("duplicate key %s, first defined on line %d", e.key, line),
+			})
+		} else {
+			defined[e.key] = e.line
+		}
+
+		// the prefix contains the key and separator, followed by optional whitespace
+		assignment := strings.TrimRightFunc(e.prefix, unicode.IsSpace)
+		key := assignment[:len(assignment)-1]
+		if strings.TrimRightFunc(key, unicode.IsSpace) != key {
+			issues = append(issues, Issue{
+				File:    file,
+				Line:    e.line,
+				Key:     e.key,
+				Rule:    RuleKeyWhitespace,
+				Message: fmt.Sprintf("key %s is followed by whitespace", e.key),
+			})
+		}
+
+		if e.quote == 0 && strings.ContainsFunc(e.value, unicode.IsSpace) {
+			issues = append(issues, Issue{
+				File:    file,
+				Line:    e.line,
+				Key:     e.key,
+				Rule:    RuleUnquotedSpace,
+				Message: fmt.Sprintf("value of %s contains spaces and should be quoted", e.key),
+			})
+		}
+	}
+
+	return issues
+}
+
+// compareKeys reports keys of the example that are missing in the file and keys of
+// the file that are not defined in the example.
+func compareKeys(file, example string, entries, exampleEntries []entry) Issues {
+	var issues Issues
+
+	keys := map[string]struct{}{}
+	for _, e := range entries {
+		keys[e.key] = struct{}{}
+	}
+	exampleKeys := map[string]struct{}{}
+	for _, e := range exampleEntries {
+		exampleKeys[e.key] = struct{}{}
+	}
+
+	reported := map[string]struct{}{}
+	for _, e := range exampleEntries {
+		if _, ok := keys[e.key]; ok {
+			continue
+		}
+		if _, ok := reported[e.key]; ok {
+			continue
+		}
+		reported[e.key] = struct{}{}
+
+		issues = append(issues, Issue{
+			File:    file,
+			Key:     e.key,
+			Rule:    RuleMissingKey,
+			Message: fmt.Sprintf("key %s of %s is missing", e.key, example),
+		})
+	}
+
+	for _, e := range entries {
+		if _, ok := exampleKeys[e.key]; ok {
+			continue
+		}
+		if _, ok := reported[e.key]; ok {
+			continue
+		}
+		reported[e.key] = struct{}{}
+
+		issues = append(issues, Issue{
+			File:    file,
+			Line:    e.line,
+			Key:     e.key,
+			Rule:    RuleExtraKey,
+			Message: fmt.Sprintf("key %s is not defined in %s", e.key, example),
+		})
+	}
+
+	return issues
+}
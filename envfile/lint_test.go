@@ -0,0 +1,82 @@
+package envfile
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	t.Parallel()
+
+	tmpdir := t.TempDir()
+	file := filepath.Join(tmpdir, ".env")
+	data := "HOST=localhost \n" +
+		"PORT =80\n" +
+		"NAME=my app\n" +
+		"MULTI=\"first  \n" +
+		"second\"\n" +
+		"HOST=otherhost\n" +
+		"EXTRA=1\n"
+	require.NoError(t, os.WriteFile(file, []byte(data), 0644))
+	example := filepath.Join(tmpdir, ".env.example")
+	require.NoError(t, os.WriteFile(example, []byte("HOST=\nPORT=\nNAME=\nMULTI=\nUSER=\n"), 0644))
+
+	issues, err := Lint(file, LintOptions{Example: example})
+	require.NoError(t, err)
+
+	expected := Issues{
+		{File: file, Line: 1, Rule: RuleTrailingWhitespace, Message: "line ends with whitespace"},
+		{File: file, Line: 2, Key: "PORT", Rule: RuleKeyWhitespace, Message: "key PORT is followed by whitespace"},
+		{File: file, Line: 3, Key: "NAME", Rule: RuleUnquotedSpace, Message: "value of NAME contains spaces and should be quoted"},
+		{File: file, Line: 6, Key: "HOST", Rule: RuleDuplicateKey, Message: "duplicate key HOST, first defined on line 1"},
+		{File: file, Line: 7, Key: "EXTRA", Rule: RuleExtraKey, Message: "key EXTRA is not defined in " + example},
+		{File: file, Key: "USER", Rule: RuleMissingKey, Message: "key USER of " + example + " is missing"},
+	}
+	assert.Equal(t, expected, issues)
+	assert.Equal(t, file+":2: key-whitespace: key PORT is followed by whitespace", issues[1].String())
+
+	encoded, err := json.Marshal(issues[5])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"file":"`+file+`","key":"USER","rule":"missing-key","message":"key USER of `+example+` is missing"}`,
+		string(encoded))
+}
+
+func TestLintSyntax(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(file, []byte("VALID=1\nINVALID LINE\n"), 0644))
+
+	issues, err := Lint(file, LintOptions{})
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, RuleSyntax, issues[0].Rule)
+	assert.Equal(t, 2, issues[0].Line)
+	assert.Contains(t, issues.String(), file+":2: syntax: ")
+
+	// all syntax errors are reported, valid lines are still compared to the example
+	require.NoError(t, os.WriteFile(file, []byte("A=1\nINVALID\nB=\"2\" x\nC=3\n=4\n"), 0644))
+	example := filepath.Join(t.TempDir(), ".env.example")
+	require.NoError(t, os.WriteFile(example, []byte("A=\nB=\nC=\n"), 0644))
+
+	issues, err = Lint(file, LintOptions{Example: example})
+	require.NoError(t, err)
+	var lines []int
+	for _, issue := range issues {
+		if issue.Rule == RuleSyntax {
+			lines = append(lines, issue.Line)
+		}
+	}
+	assert.Equal(t, []int{2, 3, 5}, lines)
+	require.Len(t, issues, 4)
+	assert.Equal(t, RuleMissingKey, issues[3].Rule)
+	assert.Equal(t, "B", issues[3].Key)
+
+	_, err = Lint(filepath.Join(t.TempDir(), "missing"), LintOptions{})
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
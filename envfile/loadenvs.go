@@ -2,12 +2,14 @@
 package envfile
 
 import (
+	"errors"
+	"io/fs"
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
-
-	"github.com/joho/godotenv"
+	"sync"
 )
 
 const (
@@ -15,9 +17,46 @@ const (
 	envPrivateFileName = ".envprivate"
 )
 
+// origin describes the env file that set an environment variable.
+type origin struct {
+	file  string
+	value string
+}
+
+// origins contains the origins of all environment variables set by this package.
+var origins sync.Map
+
+// FileResult describes the result of loading a single env file.
+type FileResult struct {
+	Path       string   // path of the env file
+	Set        []string // variables that were not set before
+	Overridden []string // variables that were already set and got overridden
+	Skipped    []string // variables that were not set as they are protected from overriding
+	Err        error    // parse error, no variables are loaded from files with errors
+}
+
+// Result describes the result of loading env files.
+type Result struct {
+	Root    string       // project root directory if the files were searched in it and it was found
+	Files   []FileResult // all files that were found, in the order they were loaded
+	Missing []string     // paths of files that were not found
+}
+
+// Err returns the joined errors of all files or nil.
+func (r *Result) Err() error {
+	var errs []error
+	for _, file := range r.Files {
+		if file.Err != nil {
+			errs = append(errs, file.Err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // Load looks for the default .env and .envprivate files in the current directory
 // and the path of the binary. It sets all environment variables from it for the
 // current process. It will overwrite existing environment variables.
+// Errors are ignored, use LoadFilesWithResult to get diagnostics.
 func Load() {
 	LoadFiles(envFileName, envPrivateFileName)
 }
@@ -26,32 +65,148 @@ func Load() {
 // If the file name contains no path, the current and executable directories will be
 // searched for the file.
 func LoadFiles(files ...string) {
+	_, _ = LoadFilesWithResult(files...)
+}
+
+// LoadFilesWithResult loads the given files like LoadFiles and returns which files
+// were found or missing and which variables were set by each file. The returned
+// error contains the parse errors of all files, including file name and line number.
+// Files with errors are skipped, all other files are loaded.
+func LoadFilesWithResult(files ...string) (*Result, error) {
+	return loadFiles("", nil, files...)
+}
+
+// loader contains the state of loading multiple env files.
+type loader struct {
+	result    *Result
+	loaded    map[string]struct{} // paths of all loaded files
+	protected map[string]struct{} // variables that must not be overridden
+}
+
+// loadFiles loads the given files from the root directory if set and the current and
+// executable directories, variables in protected are not overridden. The files are
+// loaded in the given order, every file is looked up in all directories before the
+// next file is loaded. This way a later file overrides an earlier file regardless of
+// the directory that it was found in.
+func loadFiles(root string, protected map[string]struct{}, files ...string) (*Result, error) {
+	l := &loader{
+		result:    &Result{Root: root},
+		loaded:    map[string]struct{}{},
+		protected: protected,
+	}
+
+	var directories []string
+	if root != "" {
+		directories = append(directories, root)
+	}
 	currentDirectory, err := os.Getwd()
 	if err == nil {
-		loadEnvsFromPath(currentDirectory, files...)
+		directories = append(directories, currentDirectory)
 	}
-
 	executable, err := os.Executable()
 	if err == nil {
-		executableDirectory := filepath.Dir(executable)
-		loadEnvsFromPath(executableDirectory, files...)
+		directories = append(directories, filepath.Dir(executable))
+	}
+
+	for _, fileName := range files {
+		for _, directory := range directories {
+			l.loadFile(filePath(directory, fileName))
+		}
 	}
+
+	return l.result, l.result.Err()
 }
 
-func loadEnvsFromPath(directoryPath string, files ...string) {
-	paths := make([]string, 0, len(files))
+// filePath returns the path of the file in the directory. File names that contain a
+// path are returned unchanged.
+func filePath(directory, fileName string) string {
+	if strings.ContainsAny(fileName, "/\\") {
+		return fileName
+	}
+	return path.Join(directory, fileName)
+}
 
-	for _, fileName := range files {
-		var filePath string
-		// if the file name contains a path skip prepending path
-		if strings.ContainsAny(fileName, "/\\") {
-			filePath = fileName
+// loadFile loads the given file and adds it to the result.
+func (l *loader) loadFile(filePath string) {
+	// files are only loaded once if the directories match
+	if _, ok := l.loaded[filePath]; ok {
+		return
+	}
+	l.loaded[filePath] = struct{}{}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			l.result.Missing = append(l.result.Missing, filePath)
+		} else {
+			l.result.Files = append(l.result.Files, FileResult{Path: filePath, Err: err})
+		}
+		return
+	}
+
+	l.result.Files = append(l.result.Files, l.loadEnvs(filePath, data))
+}
+
+// loadEnvs sets the variables of the given env file content in the environment.
+func (l *loader) loadEnvs(filePath string, data []byte) FileResult {
+	result := FileResult{Path: filePath}
+
+	entries, err := parse(data)
+	if err != nil {
+		result.Err = withFile(err, filePath)
+		return result
+	}
+	if err := decryptEntries(filePath, entries); err != nil {
+		result.Err = err
+		return result
+	}
+
+	values, err := newInterpolator(filePath, entries, os.LookupEnv, l.protected).expandAll()
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	for i, e := range entries {
+		if _, ok := l.protected[e.key]; ok {
+			if !slices.Contains(result.Skipped, e.key) {
+				result.Skipped = append(result.Skipped, e.key)
+			}
+			continue
+		}
+
+		value := values[i]
+		_, exists := os.LookupEnv(e.key)
+		if err := os.Setenv(e.key, value); err != nil {
+			continue
+		}
+		origins.Store(e.key, origin{file: filePath, value: value})
+
+		if slices.Contains(result.Set, e.key) || slices.Contains(result.Overridden, e.key) {
+			continue // variable defined multiple times in the same file
+		}
+		if exists {
+			result.Overridden = append(result.Overridden, e.key)
 		} else {
-			filePath = path.Join(directoryPath, fileName)
+			result.Set = append(result.Set, e.key)
 		}
+	}
 
-		paths = append(paths, filePath)
+	return result
+}
+
+// Origin returns the env file that set the current value of the given environment
+// variable. It returns false if the variable was not set by this package or has
+// been changed since.
+func Origin(key string) (string, bool) {
+	value, ok := origins.Load(key)
+	if !ok {
+		return "", false
 	}
 
-	_ = godotenv.Overload(paths...)
+	o, ok := value.(origin)
+	if !ok || o.value != os.Getenv(key) {
+		return "", false
+	}
+	return o.file, true
 }
@@ -16,7 +16,55 @@ func TestLoad(t *testing.T) {
 	file := filepath.Join(tmpdir, ".env")
 	require.NoError(t, os.WriteFile(file, []byte("TEST=1\n"), 0644))
 
-	require.NoError(t, os.Chdir(tmpdir))
+	chdir(t, tmpdir)
 	Load()
 	assert.Equal(t, "1", os.Getenv("TEST"))
 }
+
+func TestOrigin(t *testing.T) {
+	t.Setenv("ORIGIN_TEST", "")
+
+	file := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(file, []byte("ORIGIN_TEST=1\n"), 0644))
+
+	LoadFiles(file)
+	origin, ok := Origin("ORIGIN_TEST")
+	assert.True(t, ok)
+	assert.Equal(t, file, origin)
+
+	t.Setenv("ORIGIN_TEST", "2")
+	_, ok = Origin("ORIGIN_TEST")
+	assert.False(t, ok)
+}
+
+func TestLoadFilesWithResult(t *testing.T) {
+	t.Setenv("RESULT_EXISTING", "old")
+	t.Setenv("RESULT_NEW", "")
+	require.NoError(t, os.Unsetenv("RESULT_NEW"))
+
+	tmpdir := t.TempDir()
+	valid := filepath.Join(tmpdir, ".env")
+	require.NoError(t, os.WriteFile(valid, []byte("RESULT_NEW=new\nRESULT_EXISTING=${RESULT_NEW}2\n"), 0644))
+	invalid := filepath.Join(tmpdir, ".envprivate")
+	require.NoError(t, os.WriteFile(invalid, []byte("RESULT_INVALID=1\nINVALID LINE\n"), 0644))
+	missing := filepath.Join(tmpdir, ".missing")
+
+	result, err := LoadFilesWithResult(valid, invalid, missing)
+	require.ErrorIs(t, err, ErrSyntax)
+	assert.Contains(t, err.Error(), invalid+":2:")
+
+	require.Len(t, result.Files, 2)
+	assert.Equal(t, FileResult{
+		Path:       valid,
+		Set:        []string{"RESULT_NEW"},
+		Overridden: []string{"RESULT_EXISTING"},
+	}, result.Files[0])
+	assert.Equal(t, invalid, result.Files[1].Path)
+	require.Error(t, result.Files[1].Err)
+	assert.Equal(t, []string{missing}, result.Missing)
+
+	assert.Equal(t, "new", os.Getenv("RESULT_NEW"))
+	assert.Equal(t, "new2", os.Getenv("RESULT_EXISTING"))
+	_, ok := os.LookupEnv("RESULT_INVALID")
+	assert.False(t, ok)
+}
@@ -0,0 +1,228 @@
+package envfile
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const exportPrefix = "export"
+
+// ErrSyntax is returned for env files with invalid syntax.
+var ErrSyntax = errors.New("syntax error")
+
+// ParseError describes an error in an env file.
+type ParseError struct {
+	File string // path of the env file
+	Line int    // line number of the error, starting at 1
+	Err  error
+}
+
+// Error returns the error message prefixed with file and line.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s:%d: %v", e.File, e.Line, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// withFile sets the file name of a parse error.
+func withFile(err error, file string) error {
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		parseErr.File = file
+	}
+	return err
+}
+
+// entry is a variable assignment of an env file.
+type entry struct {
+	key   string
+	value string // value without quotes, escape sequences of double quoted values are resolved except \\ and \$
+	quote byte   // quote character of the value or 0 for unquoted values
+	line  int    // first line of the assignment
+	end   int    // last line of the assignment, differs from line for multi line values
+
+	raw    string // value as written in the file, including quotes
+	prefix string // text of the first line before the value
+	suffix string // text of the last line after the value, like an inline comment
+}
+
+// parse parses the content of an env file. The syntax is compatible to the
+// godotenv package: comments, export prefixes, = and : separators, single and
+// double quoted values that can span multiple lines and inline comments for
+// unquoted values are supported. Variable references are not expanded.
+// The first syntax error is returned.
+func parse(data []byte) ([]entry, error) {
+	entries, errs := parseAll(data)
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	return entries, nil
+}
+
+// parseAll parses the content of an env file like parse, but continues after lines
+// with syntax errors. It returns the entries of all valid lines and all errors.
+func parseAll(data []byte) ([]entry, []*ParseError) {
+	lines := splitLines(data)
+
+	var entries []entry
+	var errs []*ParseError
+	for i := 0; i < len(lines); i++ {
+		lineNumber := i + 1
+		text := strings.TrimLeftFunc(lines[i], unicode.IsSpace)
+		if text == "" || text[0] == '#' {
+			continue
+		}
+
+		key, rest, err := parseKey(text)
+		if err != nil {
+			errs = append(errs, &ParseError{Line: lineNumber, Err: err})
+			continue
+		}
+
+		rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+		e := entry{
+			key:    key,
+			line:   lineNumber,
+			end:    lineNumber,
+			prefix: lines[i][:len(lines[i])-len(rest)],
+		}
+		if rest == "" || (rest[0] != '"' && rest[0] != '\'') {
+			e.value = parseUnquotedValue(rest)
+			e.raw = e.value
+			e.suffix = rest[len(e.raw):]
+			entries = append(entries, e)
+			continue
+		}
+
+		e.quote = rest[0]
+		consumed, err := parseQuotedValue(&e, rest, lines[i+1:])
+		if err != nil {
+			errs = append(errs, &ParseError{Line: lineNumber, Err: err})
+			continue
+		}
+		i += consumed
+		e.end += consumed
+		entries = append(entries, e)
+	}
+
+	return entries, errs
+}
+
+// splitLines returns the lines of an env file.
+func splitLines(data []byte) []string {
+	return strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+}
+
+// parseKey returns the variable name of an assignment and the remaining text.
+func parseKey(text string) (string, string, error) {
+	if trimmed, ok := strings.CutPrefix(text, exportPrefix); ok && trimmed != "" && unicode.IsSpace(rune(trimmed[0])) {
+		text = strings.TrimLeftFunc(trimmed, unicode.IsSpace)
+	}
+
+	end := strings.IndexAny(text, "=:")
+	if end == -1 {
+		return "", "", fmt.Errorf("%w: missing = after variable name", ErrSyntax)
+	}
+
+	key := strings.TrimRightFunc(text[:end], unicode.IsSpace)
+	if key == "" {
+		return "", "", fmt.Errorf("%w: empty variable name", ErrSyntax)
+	}
+	for _, char := range key {
+		if !isKeyChar(char) {
+			return "", "", fmt.Errorf("%w: unexpected character %q in variable name %q", ErrSyntax, char, key)
+		}
+	}
+
+	return key, text[end+1:], nil
+}
+
+// isKeyChar returns whether the character is valid in a variable name.
+func isKeyChar(char rune) bool {
+	return unicode.IsLetter(char) || unicode.IsNumber(char) || char == '_' || char == '.'
+}
+
+// parseUnquotedValue returns the value without an inline comment, which has to be
+// preceded by whitespace.
+func parseUnquotedValue(text string) string {
+	for i := 1; i < len(text); i++ {
+		if text[i] == '#' && (text[i-1] == ' ' || text[i-1] == '\t') {
+			text = text[:i]
+			break
+		}
+	}
+	return strings.TrimSpace(text)
+}
+
+// parseQuotedValue sets the value of an entry from a quoted text that starts with
+// the quote character. Values can continue on the following lines, the number of
+// consumed following lines is returned.
+func parseQuotedValue(e *entry, text string, following []string) (int, error) {
+	quote := text[0]
+	value := text[1:]
+
+	for consumed := 0; ; consumed++ {
+		end := closingQuote(value, quote)
+		if end != -1 {
+			rest := strings.TrimSpace(value[end+1:])
+			if rest != "" && rest[0] != '#' {
+				return 0, fmt.Errorf("%w: unexpected characters %q after quoted value", ErrSyntax, rest)
+			}
+
+			e.raw = string(quote) + value[:end+1]
+			e.suffix = value[end+1:]
+			e.value = value[:end]
+			if quote == '"' {
+				e.value = unescape(e.value)
+			}
+			return consumed, nil
+		}
+
+		if consumed == len(following) {
+			return 0, fmt.Errorf("%w: unterminated quoted value", ErrSyntax)
+		}
+		value += "\n" + following[consumed]
+	}
+}
+
+// closingQuote returns the index of the first quote character that is not escaped
+// by a backslash, or -1.
+func closingQuote(value string, quote byte) int {
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++ // skip escaped character
+		case quote:
+			return i
+		}
+	}
+	return -1
+}
+
+var escapeRegex = regexp.MustCompile(`\\.`)
+
+// unescape resolves the escape sequences of a double quoted value. Escaped dollar
+// signs and backslashes are kept to be handled by the variable expansion, which
+// resolves them in the same pass as the references.
+func unescape(value string) string {
+	return escapeRegex.ReplaceAllStringFunc(value, func(match string) string {
+		switch match[1] {
+		case 'n':
+			return "\n"
+		case 'r':
+			return "\r"
+		case 't':
+			return "\t"
+		case '$', '\\':
+			return match
+		default:
+			return match[1:]
+		}
+	})
+}
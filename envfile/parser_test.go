@@ -0,0 +1,58 @@
+package envfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	data := "# comment\n" +
+		"PLAIN=value # inline comment\n" +
+		"export EXPORTED = exported\n" +
+		"YAML: yaml\n" +
+		"SINGLE='single $PLAIN \\n'\n" +
+		"DOUBLE=\"double\\n\\\"quoted\\\"\" # comment\n" +
+		"MULTI=\"first\r\nsecond\"\n" +
+		"EMPTY=\n" +
+		"HASH=a#b\n"
+
+	entries, err := parse([]byte(data))
+	require.NoError(t, err)
+
+	expected := []entry{
+		{key: "PLAIN", value: "value", line: 2, end: 2, raw: "value", prefix: "PLAIN=", suffix: " # inline comment"},
+		{key: "EXPORTED", value: "exported", line: 3, end: 3, raw: "exported", prefix: "export EXPORTED = "},
+		{key: "YAML", value: "yaml", line: 4, end: 4, raw: "yaml", prefix: "YAML: "},
+		{key: "SINGLE", value: "single $PLAIN \\n", quote: '\'', line: 5, end: 5,
+			raw: "'single $PLAIN \\n'", prefix: "SINGLE="},
+		{key: "DOUBLE", value: "double\n\"quoted\"", quote: '"', line: 6, end: 6,
+			raw: "\"double\\n\\\"quoted\\\"\"", prefix: "DOUBLE=", suffix: " # comment"},
+		{key: "MULTI", value: "first\nsecond", quote: '"', line: 7, end: 8, raw: "\"first\nsecond\"", prefix: "MULTI="},
+		{key: "EMPTY", value: "", line: 9, end: 9, prefix: "EMPTY="},
+		{key: "HASH", value: "a#b", line: 10, end: 10, raw: "a#b", prefix: "HASH="},
+	}
+	assert.Equal(t, expected, entries)
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		data string
+		line int
+	}{
+		{data: "VALID=1\nINVALID\n", line: 2},
+		{data: "\n\nINVALID KEY=1\n", line: 3},
+		{data: "=1\n", line: 1},
+		{data: "A=1\nB=\"unterminated\nC=3\n", line: 2},
+		{data: "A='quoted' trailing\n", line: 1},
+	}
+
+	for _, tt := range tests {
+		_, err := parse([]byte(tt.data))
+		var parseErr *ParseError
+		require.ErrorAs(t, err, &parseErr, tt.data)
+		assert.Equal(t, tt.line, parseErr.Line, tt.data)
+		require.ErrorIs(t, err, ErrSyntax)
+	}
+}
@@ -0,0 +1,49 @@
+package envfile
+
+import (
+	"fmt"
+	"os"
+)
+
+// Read parses the given env files and returns their variables without changing the
+// process environment. The files are read from the given paths and default to .env.
+// Later files override the values of earlier files. References are resolved against
+// the earlier files and the process environment.
+func Read(files ...string) (map[string]string, error) {
+	if len(files) == 0 {
+		files = []string{envFileName}
+	}
+
+	variables := map[string]string{}
+	lookupEnv := func(key string) (string, bool) {
+		if value, ok := variables[key]; ok {
+			return value, true
+		}
+		return os.LookupEnv(key)
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading env file: %w", err)
+		}
+
+		entries, err := parse(data)
+		if err != nil {
+			return nil, withFile(err, file)
+		}
+		if err := decryptEntries(file, entries); err != nil {
+			return nil, err
+		}
+
+		values, err := newInterpolator(file, entries, lookupEnv, nil).expandAll()
+		if err != nil {
+			return nil, err
+		}
+		for i, e := range entries {
+			variables[e.key] = values[i]
+		}
+	}
+
+	return variables, nil
+}
@@ -0,0 +1,58 @@
+package envfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	tmpdir := t.TempDir()
+	first := filepath.Join(tmpdir, ".env")
+	require.NoError(t, os.WriteFile(first, []byte("READ_HOST=localhost\nREAD_PORT=80\n"), 0644))
+	second := filepath.Join(tmpdir, ".env.local")
+	require.NoError(t, os.WriteFile(second, []byte("READ_PORT=8080\nREAD_URL=${READ_HOST}:${READ_PORT}\n"), 0644))
+
+	variables, err := Read(first, second)
+	require.NoError(t, err)
+	expected := map[string]string{
+		"READ_HOST": "localhost",
+		"READ_PORT": "8080",
+		"READ_URL":  "localhost:8080",
+	}
+	assert.Equal(t, expected, variables)
+
+	_, ok := os.LookupEnv("READ_HOST")
+	assert.False(t, ok)
+
+	_, err = Read(filepath.Join(tmpdir, "missing"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	invalid := filepath.Join(tmpdir, ".env.invalid")
+	require.NoError(t, os.WriteFile(invalid, []byte("INVALID\n"), 0644))
+	_, err = Read(invalid)
+	require.ErrorContains(t, err, invalid+":1:")
+}
+
+func TestSetForTest(t *testing.T) {
+	t.Setenv("SET_FOR_TEST_EXISTING", "old")
+	require.NoError(t, os.Unsetenv("SET_FOR_TEST_NEW"))
+
+	t.Run("set", func(t *testing.T) {
+		SetForTest(t, map[string]string{
+			"SET_FOR_TEST_EXISTING": "new",
+			"SET_FOR_TEST_NEW":      "new",
+		})
+		assert.Equal(t, "new", os.Getenv("SET_FOR_TEST_EXISTING"))
+		assert.Equal(t, "new", os.Getenv("SET_FOR_TEST_NEW"))
+	})
+
+	assert.Equal(t, "old", os.Getenv("SET_FOR_TEST_EXISTING"))
+	_, ok := os.LookupEnv("SET_FOR_TEST_NEW")
+	assert.False(t, ok)
+}
@@ -0,0 +1,72 @@
+package envfile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrRootNotFound is returned if no parent directory contains a root marker.
+var ErrRootNotFound = errors.New("project root not found")
+
+// DefaultRootMarkers are the file names that mark a project root directory.
+var DefaultRootMarkers = []string{"go.mod", ".git"}
+
+// FindRoot returns the first directory that contains one of the given marker files
+// or directories, starting at the given directory and walking up its parents.
+// The markers default to DefaultRootMarkers.
+func FindRoot(directory string, markers ...string) (string, error) {
+	if len(markers) == 0 {
+		markers = DefaultRootMarkers
+	}
+
+	directory, err := filepath.Abs(directory)
+	if err != nil {
+		return "", fmt.Errorf("getting absolute path: %w", err)
+	}
+
+	for {
+		for _, marker := range markers {
+			if _, err := os.Stat(filepath.Join(directory, marker)); err == nil {
+				return directory, nil
+			}
+		}
+
+		parent := filepath.Dir(directory)
+		if parent == directory {
+			return "", fmt.Errorf("%w: no directory contains any of %v", ErrRootNotFound, markers)
+		}
+		directory = parent
+	}
+}
+
+// LoadFilesFromRoot loads the given files like LoadFilesWithResult, but also from the
+// project root directory, which is found by walking up from the current directory
+// until a directory contains one of the DefaultRootMarkers. The root is loaded first,
+// its values can be overridden by files of the current and executable directories.
+// The resolved root directory is returned in the result. If no root is found, for
+// example in a deployed container, the files are only loaded from the current and
+// executable directories and the root of the result is empty.
+func LoadFilesFromRoot(files ...string) (*Result, error) {
+	root, err := findRootFromWorkingDirectory(nil)
+	if err != nil {
+		return nil, err
+	}
+	return loadFiles(root, nil, files...)
+}
+
+// findRootFromWorkingDirectory returns the project root directory of the current
+// directory or an empty string if no root is found.
+func findRootFromWorkingDirectory(markers []string) (string, error) {
+	currentDirectory, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("getting working directory: %w", err)
+	}
+
+	root, err := FindRoot(currentDirectory, markers...)
+	if errors.Is(err, ErrRootNotFound) {
+		return "", nil
+	}
+	return root, err
+}
@@ -0,0 +1,80 @@
+package envfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cornelk/gotokit/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindRoot(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	subpackage := filepath.Join(root, "internal", "pkg")
+	require.NoError(t, os.MkdirAll(subpackage, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module test\n"), 0644))
+
+	found, err := FindRoot(subpackage)
+	require.NoError(t, err)
+	assert.Equal(t, root, found)
+
+	found, err = FindRoot(subpackage, "pkg")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "internal"), found)
+
+	_, err = FindRoot(subpackage, "missing-marker")
+	require.ErrorIs(t, err, ErrRootNotFound)
+}
+
+func TestLoadFilesFromRoot(t *testing.T) {
+	t.Setenv("ROOT_TEST", "")
+	t.Setenv("ROOT_CASCADE_TEST", "")
+
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	subpackage := filepath.Join(root, "internal", "pkg")
+	require.NoError(t, os.MkdirAll(subpackage, 0755))
+	require.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".env"), []byte("ROOT_TEST=root\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".env.test"), []byte("ROOT_CASCADE_TEST=test\n"), 0644))
+	chdir(t, subpackage)
+
+	result, err := LoadFilesFromRoot(".env")
+	require.NoError(t, err)
+	assert.Equal(t, root, result.Root)
+	require.NotEmpty(t, result.Files)
+	assert.Equal(t, filepath.Join(root, ".env"), result.Files[0].Path)
+	assert.Contains(t, result.Missing, filepath.Join(subpackage, ".env"))
+	assert.Equal(t, "root", os.Getenv("ROOT_TEST"))
+
+	result, err = LoadEnvironment(env.Test, CascadeOptions{SearchRoot: true})
+	require.NoError(t, err)
+	assert.Equal(t, root, result.Root)
+	assert.Equal(t, "test", os.Getenv("ROOT_CASCADE_TEST"))
+}
+
+func TestLoadFilesFromRootNotFound(t *testing.T) {
+	t.Setenv("ROOT_FALLBACK_TEST", "")
+
+	// temporary directories are usually not inside a project
+	directory, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	if _, err := FindRoot(directory); err == nil {
+		t.Skip("temporary directory is inside a project root")
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(directory, ".env"), []byte("ROOT_FALLBACK_TEST=current\n"), 0644))
+	chdir(t, directory)
+
+	result, err := LoadFilesFromRoot(".env")
+	require.NoError(t, err)
+	assert.Empty(t, result.Root)
+	assert.Equal(t, "current", os.Getenv("ROOT_FALLBACK_TEST"))
+
+	result, err = LoadEnvironment("", CascadeOptions{SearchRoot: true})
+	require.NoError(t, err)
+	assert.Empty(t, result.Root)
+}
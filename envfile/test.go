@@ -0,0 +1,44 @@
+package envfile
+
+import (
+	"os"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestingT is a subset of the API provided by all *testing.T and
+// *testing.B objects.
+type TestingT interface {
+	// Cleanup registers a function to be called when the test completes.
+	Cleanup(func())
+
+	// Errorf logs the given message and marks the test as failed.
+	Errorf(string, ...any)
+
+	// FailNow marks the test as failed and stops execution of that test.
+	FailNow()
+
+	// Helper marks the calling function as a test helper function.
+	Helper()
+}
+
+// SetForTest sets the given variables in the process environment and restores
+// the previous environment when the test completes. Like testing.T.Setenv it
+// must not be used in parallel tests, prefer passing the variables directly,
+// for example by using config.Options.Variables.
+func SetForTest(t TestingT, variables map[string]string) {
+	t.Helper()
+
+	for key, value := range variables {
+		previous, existed := os.LookupEnv(key)
+		t.Cleanup(func() {
+			if existed {
+				_ = os.Setenv(key, previous)
+			} else {
+				_ = os.Unsetenv(key)
+			}
+		})
+
+		require.NoError(t, os.Setenv(key, value))
+	}
+}
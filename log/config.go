@@ -1,7 +1,6 @@
 package log
 
 import (
-	"fmt"
 	"io"
 	"log/slog"
 
@@ -31,27 +30,14 @@ type Config struct {
 	TimeFormat string
 }
 
-// ConfigForEnv returns the default config for the given environment.
+// ConfigForEnv returns the default config for the given environment, as defined
+// by the logging profile of the environment, see RegisterProfile.
 // The returned config can be adjusted and used to create a logger with
 // custom config using the NewWithConfig() function.
 func ConfigForEnv(environment env.Environment) (Config, error) {
-	cfg := Config{
-		Level:      DefaultLevel(),
-		TimeFormat: DefaultTimeFormat,
+	profile, err := profileForEnv(environment)
+	if err != nil {
+		return Config{}, err
 	}
-
-	switch environment {
-	case env.Test, env.Development:
-		cfg.JSONOutput = false
-		cfg.CallerInfo = true
-
-	case env.Staging, env.Production:
-		cfg.JSONOutput = true
-		cfg.CallerInfo = false
-
-	default:
-		return Config{}, fmt.Errorf("invalid environment specified '%v'", environment)
-	}
-
-	return cfg, nil
+	return profile(), nil
 }
@@ -0,0 +1,81 @@
+package log
+
+import (
+	"os"
+	"testing"
+
+	"github.com/cornelk/gotokit/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigForEnv(t *testing.T) {
+	cfg, err := ConfigForEnv(env.Production)
+	require.NoError(t, err)
+	assert.True(t, cfg.JSONOutput)
+	assert.False(t, cfg.CallerInfo)
+
+	require.NoError(t, env.Register("perf", env.Definition{ProductionLike: true}))
+	cfg, err = ConfigForEnv("perf")
+	require.NoError(t, err)
+	assert.True(t, cfg.JSONOutput)
+	assert.False(t, cfg.CallerInfo)
+
+	cfg, err = ConfigForEnv(env.Local)
+	require.NoError(t, err)
+	assert.False(t, cfg.JSONOutput)
+	assert.True(t, cfg.CallerInfo)
+
+	_, err = ConfigForEnv("invalid")
+	require.Error(t, err)
+}
+
+func TestConfigForEnvProfiles(t *testing.T) {
+	for _, environment := range []env.Environment{env.Local, env.Test, env.Development, env.Qa} {
+		cfg, err := ConfigForEnv(environment)
+		require.NoError(t, err)
+		assert.False(t, cfg.JSONOutput, environment)
+	}
+
+	t.Cleanup(resetProfiles)
+	require.NoError(t, env.Register("sandbox", env.Definition{Aliases: []string{"sbx"}}))
+	require.NoError(t, RegisterProfile("sbx", func() Config {
+		return Config{JSONOutput: true, Level: DebugLevel, TimeFormat: "-"}
+	}))
+	cfg, err := ConfigForEnv("sandbox")
+	require.NoError(t, err)
+	assert.True(t, cfg.JSONOutput)
+	assert.Equal(t, DebugLevel, cfg.Level)
+
+	require.Error(t, RegisterProfile("invalid", ConsoleProfile))
+
+	resetProfiles()
+	cfg, err = ConfigForEnv("sandbox")
+	require.NoError(t, err)
+	assert.False(t, cfg.JSONOutput)
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv(EnvLevel, "trace")
+	t.Setenv(EnvFormat, "console")
+	t.Setenv(EnvCaller, "true")
+	t.Setenv(EnvTimeFormat, "-")
+	t.Setenv(EnvOutput, "stderr")
+
+	cfg, err := LoadConfig(env.Production)
+	require.NoError(t, err)
+	assert.Equal(t, TraceLevel, cfg.Level)
+	assert.False(t, cfg.JSONOutput)
+	assert.True(t, cfg.CallerInfo)
+	assert.Equal(t, "-", cfg.TimeFormat)
+	assert.Equal(t, os.Stderr, cfg.Output)
+
+	t.Setenv(EnvFormat, "xml")
+	_, err = LoadConfig(env.Production)
+	require.ErrorContains(t, err, EnvFormat)
+
+	t.Setenv(EnvFormat, "")
+	t.Setenv(EnvLevel, "loud")
+	_, err = LoadConfig(env.Production)
+	require.ErrorContains(t, err, EnvLevel)
+}
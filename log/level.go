@@ -1,7 +1,9 @@
 package log
 
 import (
+	"fmt"
 	"log/slog"
+	"strings"
 	"sync/atomic"
 )
 
@@ -50,6 +52,23 @@ func SetDefaultLevel(level Level) {
 	atomic.StoreUintptr(&defaultLevel, uintptr(level))
 }
 
+// ParseLevel parses a level name like "info" or "DEBUG+2" case-insensitively,
+// including the names TRACE and FATAL of the additional levels of this package.
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case traceLevelText.String():
+		return TraceLevel, nil
+	case fatalLevelText.String():
+		return FatalLevel, nil
+	}
+
+	var level Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("parsing level: %w", err)
+	}
+	return level, nil
+}
+
 // ReplaceLevelName sets custom defined level names for outputting.
 func ReplaceLevelName(_ []string, a slog.Attr) slog.Attr {
 	if a.Key != slog.LevelKey {
@@ -0,0 +1,29 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]Level{
+		"trace":  TraceLevel,
+		"DEBUG":  DebugLevel,
+		"Info":   InfoLevel,
+		"warn":   WarnLevel,
+		"ERROR":  ErrorLevel,
+		"fatal":  FatalLevel,
+		"INFO+2": InfoLevel + 2,
+	}
+
+	for name, expected := range tests {
+		level, err := ParseLevel(name)
+		require.NoError(t, err)
+		assert.Equal(t, expected, level, name)
+	}
+
+	_, err := ParseLevel("invalid")
+	require.Error(t, err)
+}
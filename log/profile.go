@@ -0,0 +1,167 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cornelk/gotokit/env"
+)
+
+// Environment variables that override the values of a logging profile in LoadConfig.
+const (
+	EnvLevel      = "LOG_LEVEL"       // level name like debug or TRACE
+	EnvFormat     = "LOG_FORMAT"      // json or console
+	EnvCaller     = "LOG_CALLER"      // boolean that enables caller info
+	EnvTimeFormat = "LOG_TIME_FORMAT" // Go time layout, - disables the time for the console format
+	EnvOutput     = "LOG_OUTPUT"      // stdout or stderr
+)
+
+// Profile returns the logging config for an environment. It is called on every
+// lookup, which allows profiles to use the current DefaultLevel().
+type Profile func() Config
+
+var (
+	profilesMu sync.RWMutex
+
+	// profiles contains the logging profiles of all environments.
+	profiles = defaultProfiles()
+)
+
+// defaultProfiles returns the logging profiles of the built-in environments.
+func defaultProfiles() map[env.Environment]Profile {
+	return map[env.Environment]Profile{
+		env.Local:       ConsoleProfile,
+		env.Test:        ConsoleProfile,
+		env.Development: ConsoleProfile,
+		env.Qa:          ConsoleProfile,
+		env.Staging:     JSONProfile,
+		env.Production:  JSONProfile,
+	}
+}
+
+// resetProfiles removes all registered profiles, only the profiles of the built-in
+// environments are kept. It is intended for tests.
+func resetProfiles() {
+	profilesMu.Lock()
+	profiles = defaultProfiles()
+	profilesMu.Unlock()
+}
+
+// ConsoleProfile returns a config for human readable console output including
+// caller info, as used for local and development environments.
+func ConsoleProfile() Config {
+	return Config{
+		CallerInfo: true,
+		Level:      DefaultLevel(),
+		TimeFormat: DefaultTimeFormat,
+	}
+}
+
+// JSONProfile returns a config for JSON output without caller info, as used for
+// production-like environments.
+func JSONProfile() Config {
+	return Config{
+		JSONOutput: true,
+		Level:      DefaultLevel(),
+		TimeFormat: DefaultTimeFormat,
+	}
+}
+
+// RegisterProfile sets the logging profile of an environment. The environment has
+// to be registered in the env package, aliases are resolved.
+func RegisterProfile(environment env.Environment, profile Profile) error {
+	environment, err := env.Parse(string(environment))
+	if err != nil {
+		return fmt.Errorf("parsing environment: %w", err)
+	}
+
+	profilesMu.Lock()
+	profiles[environment] = profile
+	profilesMu.Unlock()
+	return nil
+}
+
+// profileForEnv returns the logging profile of an environment. Environments without
+// a registered profile use the profile matching their production-like classification.
+func profileForEnv(name env.Environment) (Profile, error) {
+	environment, err := env.Parse(string(name))
+	if err != nil {
+		return nil, fmt.Errorf("invalid environment specified '%v'", name)
+	}
+
+	profilesMu.RLock()
+	profile, ok := profiles[environment]
+	profilesMu.RUnlock()
+	if ok {
+		return profile, nil
+	}
+
+	if environment.IsProductionLike() {
+		return JSONProfile, nil
+	}
+	return ConsoleProfile, nil
+}
+
+// LoadConfig returns the config of the profile for the given environment with
+// the overrides of the LOG_* environment variables applied.
+func LoadConfig(environment env.Environment) (Config, error) {
+	cfg, err := ConfigForEnv(environment)
+	if err != nil {
+		return Config{}, err
+	}
+	if err := cfg.ApplyEnvOverrides(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// ApplyEnvOverrides sets the values of the config that are set in the LOG_*
+// environment variables.
+func (cfg *Config) ApplyEnvOverrides() error {
+	if value := os.Getenv(EnvLevel); value != "" {
+		level, err := ParseLevel(value)
+		if err != nil {
+			return fmt.Errorf("parsing %s: %w", EnvLevel, err)
+		}
+		cfg.Level = level
+	}
+
+	if value := os.Getenv(EnvFormat); value != "" {
+		switch strings.ToLower(value) {
+		case "json":
+			cfg.JSONOutput = true
+		case "console", "text":
+			cfg.JSONOutput = false
+		default:
+			return fmt.Errorf("invalid %s '%s'", EnvFormat, value)
+		}
+	}
+
+	if value := os.Getenv(EnvCaller); value != "" {
+		caller, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("parsing %s: %w", EnvCaller, err)
+		}
+		cfg.CallerInfo = caller
+	}
+
+	if value := os.Getenv(EnvTimeFormat); value != "" {
+		cfg.TimeFormat = value
+	}
+
+	if value := os.Getenv(EnvOutput); value != "" {
+		switch strings.ToLower(value) {
+		case "stdout":
+			cfg.Output = os.Stdout
+		case "stderr":
+			cfg.Output = os.Stderr
+		default:
+			return fmt.Errorf("invalid %s '%s'", EnvOutput, value)
+		}
+	}
+
+	return nil
+}